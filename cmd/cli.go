@@ -1,63 +1,388 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/gcp"
+	"github.com/arehmandev/gcp-nuke/gcp/cassette"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"github.com/arehmandev/gcp-nuke/notify"
 	"github.com/urfave/cli/v2"
 )
 
+// Defaults of --timeout and --polltime, also used when only per resource type values are given
+const (
+	defaultTimeout  = "400s"
+	defaultPollTime = "10s"
+)
+
 // Command -
 func Command() {
+	err := newApp().Run(os.Args)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
 
-	app := &cli.App{
+// newApp - the gcp-nuke command line, with every flag and the serve command
+func newApp() *cli.App {
+	return &cli.App{
 		Usage:     "The GCP project cleanup tool with added radiation",
 		Version:   "v0.1.0",
 		UsageText: "e.g. gcp-nuke --project test-nuke-262510 --dryrun",
 		Flags: []cli.Flag{
 			&cli.StringFlag{
-				Name:     "project, p",
-				Usage:    "GCP project id to nuke (required)",
-				Required: true,
+				Name:  "project, p",
+				Usage: "GCP project id to nuke (required)",
 			},
 			&cli.BoolFlag{
 				Name:  "dryrun, d",
 				Usage: "Perform a dryrun instead",
 			},
-			&cli.IntFlag{
+			&cli.StringSliceFlag{
 				Name:  "timeout, t",
-				Value: 400,
-				Usage: "Timeout for removal of a single resource in seconds",
+				Value: cli.NewStringSlice(defaultTimeout),
+				Usage: "Timeout for removal of a single resource e.g. 10m, or per resource type e.g. ContainerGKEClusters=30m",
 			},
-			&cli.IntFlag{
+			&cli.StringSliceFlag{
 				Name:  "polltime, p",
+				Value: cli.NewStringSlice(defaultPollTime),
+				Usage: "Time for polling resource deletion status e.g. 10s, or per resource type e.g. ContainerGKEClusters=30s",
+			},
+			&cli.StringFlag{
+				Name:  "config, c",
+				Usage: "Path to a yaml config file with timeouts and polltimes, flags take precedence",
+			},
+			&cli.IntFlag{
+				Name:  "max-concurrency",
+				Value: 20,
+				Usage: "Maximum number of resources being deleted at once, across all resource types",
+			},
+			&cli.Float64Flag{
+				Name:  "rate-limit",
 				Value: 10,
-				Usage: "Time for polling resource deletion status in seconds",
+				Usage: "Maximum requests per second to each GCP API (compute, container...)",
+			},
+			&cli.StringFlag{
+				Name:  "credentials-file",
+				Usage: "Path to a service account or user credentials json, application default credentials are used otherwise",
+			},
+			&cli.StringFlag{
+				Name:  "impersonate-service-account",
+				Usage: "Email of a service account to impersonate, requires roles/iam.serviceAccountTokenCreator on it",
+			},
+			&cli.StringFlag{
+				Name:  "quota-project",
+				Usage: "Project to bill API quota against, instead of the project of the credentials",
+			},
+			&cli.StringFlag{
+				Name:  "endpoint",
+				Usage: "Override the host of every GCP API e.g. http://localhost:8080 for a local fake server",
+			},
+			&cli.BoolFlag{
+				Name:  "no-auth",
+				Usage: "Send API requests without credentials, only useful with --endpoint",
+			},
+			&cli.StringFlag{
+				Name:  "metrics-addr",
+				Usage: "Serve prometheus metrics on /metrics at this address during the run e.g. :9090",
+			},
+			&cli.StringFlag{
+				Name:  "pushgateway-url",
+				Usage: "Push prometheus metrics to this pushgateway once the run completes e.g. http://pushgateway:9091",
 			},
+			&cli.StringSliceFlag{
+				Name:  "webhook-url",
+				Usage: "Post a json summary to this webhook when the run completes or fails, can be repeated",
+			},
+			&cli.StringSliceFlag{
+				Name:  "slack-webhook-url",
+				Usage: "Post a message to this slack incoming webhook when the run completes or fails, can be repeated",
+			},
+			&cli.BoolFlag{
+				Name:  "notify-plan",
+				Usage: "Also send the plan to --webhook-url and --slack-webhook-url before anything is deleted",
+			},
+			&cli.StringFlag{
+				Name:  "trace-exporter",
+				Usage: "Export OpenTelemetry traces of the run with otlp (configured by the OTEL_EXPORTER_OTLP_* environment variables) or stdout",
+			},
+			&cli.StringFlag{
+				Name:  "record-cassette",
+				Usage: "Record every API request and response to a cassette file, with tokens scrubbed, for replaying in tests",
+			},
+			&cli.StringSliceFlag{
+				Name:  "ttl-label",
+				Usage: "Only delete resources past the expiry in this label, a date e.g. expires-at=2026-10-20 or a lifetime since creation e.g. ttl=48h or ttl=7d, can be repeated",
+			},
+			&cli.StringFlag{
+				Name:  "ttl-default",
+				Usage: "Lifetime of resources without a --ttl-label e.g. 72h or 7d, by default they are never deleted",
+			},
+			&cli.StringSliceFlag{
+				Name:  "owner-label",
+				Usage: "Label holding the owner of a resource, the plan is grouped by owner, can be repeated (default: owner, team)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "owner",
+				Usage: "Only delete resources of this owner, or unowned for resources without one, can be repeated",
+			},
+			&cli.StringSliceFlag{
+				Name:  "name-regex",
+				Usage: "Only delete resources with a name matching this regular expression e.g. ^it-[a-z0-9]+$, can be repeated",
+			},
+			&cli.StringSliceFlag{
+				Name:  "email-regex",
+				Usage: "Only delete service accounts, and their keys, with an email matching this regular expression e.g. ^it-.*@, can be repeated",
+			},
+			&cli.StringFlag{
+				Name:  "audit-log",
+				Usage: "Export of the admin activity audit logs in json, e.g. from gcloud logging read --format json, to find the creator of resources without an owner label",
+			},
+			&cli.StringFlag{
+				Name:  "price-table",
+				Usage: "Yaml or json file updating prices in the bundled price table used for cost estimates",
+			},
+			&cli.StringFlag{
+				Name:  "plan-file",
+				Usage: "Write the plan, with owners and cost estimates, to this file as json before anything is deleted",
+			},
+			&cli.BoolFlag{
+				Name:  "snapshot-disks",
+				Usage: "Snapshot every disk before deleting it, including the disks of deleted instances",
+			},
+			&cli.StringFlag{
+				Name:  "snapshot-skip-label",
+				Usage: "Disks with this label are deleted without a snapshot (default: no-snapshot)",
+			},
+			&cli.BoolFlag{
+				Name:  "disable-deletion-protection",
+				Usage: "Turn off deletion protection on Cloud SQL instances so they can be deleted, by default protected instances are reported and left",
+			},
+			&cli.BoolFlag{
+				Name:  "include-default-service-accounts",
+				Usage: "Also delete the default compute and App Engine service accounts, and their keys, which are kept by default",
+			},
+		},
+		Commands: []*cli.Command{
+			serveCommand(),
 		},
 		Action: func(c *cli.Context) error {
+			// Not a required flag, as gcp-nuke serve takes its projects from the config file
+			if !c.IsSet("project") {
+				cli.ShowAppHelp(c)
+				return fmt.Errorf("Required flag \"project\" not set")
+			}
 
 			// Behaviour to delete all resource in parallel in one project at a time - will be made into loop / concurrenct project nuke if required
-			config := config.Config{
-				Project:  c.String("project"),
-				DryRun:   c.Bool("dryrun"),
-				Timeout:  c.Int("timeout"),
-				PollTime: c.Int("polltime"),
-				Context:  gcp.Ctx,
-				Zones:    gcp.GetZones(gcp.Ctx, c.String("project")),
-				Regions:  gcp.GetRegions(gcp.Ctx, c.String("project")),
-			}
-			log.Printf("[Info] Timeout %v seconds. Polltime %v seconds. Dry run: %v", config.Timeout, config.PollTime, config.DryRun)
-			gcp.RemoveProject(config)
-
-			return nil
+			ctx, cancel := context.WithCancel(gcp.Ctx)
+			defer cancel()
+			config, err := configFromFlags(c, ctx)
+			if err != nil {
+				return err
+			}
+
+			stopTracing, err := startTracing(c)
+			if err != nil {
+				return err
+			}
+			defer stopTracing()
+
+			// Every API client is built from the same credentials, now that the flags are parsed
+			clients := gcp.NewClients(config)
+			if c.IsSet("record-cassette") {
+				recorder := cassette.NewRecorder(http.DefaultTransport)
+				clients.Transport = recorder
+				defer func() {
+					if err := recorder.Save(c.String("record-cassette")); err != nil {
+						log.Printf("[Error] Saving cassette: %v", err)
+					}
+				}()
+			}
+			if config.Zones, err = gcp.GetZones(clients, config.Project); err != nil {
+				return err
+			}
+			if config.Regions, err = gcp.GetRegions(clients, config.Project); err != nil {
+				return err
+			}
+			if c.IsSet("metrics-addr") {
+				go func() {
+					log.Printf("[Info] Serving metrics on %v/metrics", c.String("metrics-addr"))
+					if err := gcp.ServeMetrics(c.String("metrics-addr")); err != nil {
+						log.Printf("[Error] Serving metrics: %v", err)
+					}
+				}()
+			}
+			helpers.SetupCloseHandler(cancel)
+			err = gcp.RemoveProject(config, clients)
+
+			// Metrics are pushed even when the run fails, as that is when they are most useful
+			if c.IsSet("pushgateway-url") {
+				if pushErr := gcp.PushMetrics(c.String("pushgateway-url"), config.Project); pushErr != nil {
+					log.Printf("[Error] Pushing metrics: %v", pushErr)
+				}
+			}
+			return err
 		},
 	}
+}
+
+// configFromFlags - the config from the global flags and the --config file, shared by every command
+func configFromFlags(c *cli.Context, ctx context.Context) (config.Config, error) {
+	// Webhooks from flags, on top of any notifications in the config file
+	notifyEvents := []string{notify.EventCompleted, notify.EventFailed}
+	if c.Bool("notify-plan") {
+		notifyEvents = append(notifyEvents, notify.EventPlan)
+	}
+	notifications := []config.Notification{}
+	for _, url := range c.StringSlice("webhook-url") {
+		notifications = append(notifications, config.Notification{URL: url, Format: notify.FormatWebhook, Events: notifyEvents})
+	}
+	for _, url := range c.StringSlice("slack-webhook-url") {
+		notifications = append(notifications, config.Notification{URL: url, Format: notify.FormatSlack, Events: notifyEvents})
+	}
+
+	var ttlDefault time.Duration
+	if c.IsSet("ttl-default") {
+		var err error
+		if ttlDefault, err = config.ParseTTL(c.String("ttl-default")); err != nil {
+			return config.Config{}, err
+		}
+	}
+
+	namePatterns := []*regexp.Regexp{}
+	for _, pattern := range c.StringSlice("name-regex") {
+		namePattern, err := regexp.Compile(pattern)
+		if err != nil {
+			return config.Config{}, fmt.Errorf("invalid --name-regex %q: %v", pattern, err)
+		}
+		namePatterns = append(namePatterns, namePattern)
+	}
+	emailPatterns := []*regexp.Regexp{}
+	for _, pattern := range c.StringSlice("email-regex") {
+		emailPattern, err := regexp.Compile(pattern)
+		if err != nil {
+			return config.Config{}, fmt.Errorf("invalid --email-regex %q: %v", pattern, err)
+		}
+		emailPatterns = append(emailPatterns, emailPattern)
+	}
+
+	config := config.Config{
+		Project: c.String("project"),
+		DryRun:  c.Bool("dryrun"),
+		Context: ctx,
+
+		MaxConcurrency: c.Int("max-concurrency"),
+		RateLimit:      c.Float64("rate-limit"),
+
+		CredentialsFile:           c.String("credentials-file"),
+		ImpersonateServiceAccount: c.String("impersonate-service-account"),
+		QuotaProject:              c.String("quota-project"),
+
+		Endpoint:              c.String("endpoint"),
+		WithoutAuthentication: c.Bool("no-auth"),
+
+		Notifications: notifications,
+		TTLLabels:     c.StringSlice("ttl-label"),
+		OwnerLabels:   c.StringSlice("owner-label"),
+		Owners:        c.StringSlice("owner"),
+		NamePatterns:  namePatterns,
+		EmailPatterns: emailPatterns,
+		AuditLog:      c.String("audit-log"),
+		PriceTable:    c.String("price-table"),
+		PlanFile:      c.String("plan-file"),
+
+		SnapshotDisks:     c.Bool("snapshot-disks"),
+		SnapshotSkipLabel: c.String("snapshot-skip-label"),
+
+		DisableDeletionProtection:     c.Bool("disable-deletion-protection"),
+		IncludeDefaultServiceAccounts: c.Bool("include-default-service-accounts"),
+	}
+	if c.IsSet("config") {
+		if err := config.LoadFile(c.String("config")); err != nil {
+			return config, err
+		}
+	}
+	if c.IsSet("ttl-default") {
+		config.TTLDefault = ttlDefault
+	}
+	// Flag defaults only apply when the config file doesn't set a global value
+	if c.IsSet("timeout") || config.Timeout == 0 {
+		if err := config.SetTimeouts(c.StringSlice("timeout")); err != nil {
+			return config, err
+		}
+	}
+	if c.IsSet("polltime") || config.PollTime == 0 {
+		if err := config.SetPollTimes(c.StringSlice("polltime")); err != nil {
+			return config, err
+		}
+	}
+	// Only per resource type values were given, e.g. --timeout ContainerGKEClusters=30m, so the rest keep the defaults.
+	// A global value of zero never gets here, parsing rejects it
+	if config.Timeout == 0 {
+		if err := config.SetTimeouts([]string{defaultTimeout}); err != nil {
+			return config, err
+		}
+	}
+	if config.PollTime == 0 {
+		if err := config.SetPollTimes([]string{defaultPollTime}); err != nil {
+			return config, err
+		}
+	}
 
-	err := app.Run(os.Args)
+	log.Printf("[Info] Timeout %v. Polltime %v. Dry run: %v", config.Timeout, config.PollTime, config.DryRun)
+	for resourceName := range config.Resources {
+		log.Printf("[Info] %v overrides: Timeout %v. Polltime %v", resourceName, config.TimeoutFor(resourceName), config.PollTimeFor(resourceName))
+	}
+	log.Printf("[Info] Max concurrency %v. Rate limit %v requests per second per API", config.MaxConcurrency, config.RateLimit)
+	if config.ImpersonateServiceAccount != "" {
+		log.Printf("[Info] Impersonating service account %v", config.ImpersonateServiceAccount)
+	}
+	if len(config.TTLLabels) > 0 {
+		log.Printf("[Info] Only deleting expired resources, ttl labels %v. Default ttl %v", config.TTLLabels, config.TTLDefault)
+	} else if config.TTLDefault > 0 {
+		return config, fmt.Errorf("--ttl-default needs at least one --ttl-label")
+	}
+	if config.SnapshotDisks {
+		log.Printf("[Info] Snapshotting disks before deleting them")
+	}
+	if config.DisableDeletionProtection {
+		log.Printf("[Info] Turning off deletion protection of Cloud SQL instances before deleting them")
+	}
+	if len(config.Owners) > 0 {
+		log.Printf("[Info] Only deleting resources of owners %v", config.Owners)
+	}
+	if len(config.NamePatterns) > 0 {
+		log.Printf("[Info] Only deleting resources with names matching %v", config.NamePatterns)
+	}
+	if len(config.EmailPatterns) > 0 {
+		log.Printf("[Info] Only deleting service accounts with emails matching %v", config.EmailPatterns)
+	}
+	if config.IncludeDefaultServiceAccounts {
+		log.Printf("[Info] Including the default compute and App Engine service accounts")
+	}
+	return config, nil
+}
+
+// startTracing - sets up --trace-exporter, if set, the returned stop flushes the remaining spans
+func startTracing(c *cli.Context) (stop func(), err error) {
+	if !c.IsSet("trace-exporter") {
+		return func() {}, nil
+	}
+	shutdown, err := gcp.SetupTracing(context.Background(), c.String("trace-exporter"), os.Stdout)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	return func() {
+		if err := shutdown(context.Background()); err != nil {
+			log.Printf("[Error] Exporting traces: %v", err)
+		}
+	}, nil
 }
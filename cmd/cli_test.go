@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/urfave/cli/v2"
+)
+
+// parseFlags - the config from the command line, without running anything
+func parseFlags(args ...string) (config.Config, error) {
+	var parsed config.Config
+	app := newApp()
+	app.Action = func(c *cli.Context) error {
+		var err error
+		parsed, err = configFromFlags(c, context.Background())
+		return err
+	}
+	err := app.Run(append([]string{"gcp-nuke", "--project", "p"}, args...))
+	return parsed, err
+}
+
+func TestConfigFromFlagsPerResourceType(t *testing.T) {
+	parsed, err := parseFlags("--timeout", "ContainerGKEClusters=30m", "--polltime", "ContainerGKEClusters=30s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Timeout != 400*time.Second || parsed.PollTime != 10*time.Second {
+		t.Errorf("expected the other resource types to keep the 400s timeout and 10s polltime, got: %v %v", parsed.Timeout, parsed.PollTime)
+	}
+	if timeout, pollTime := parsed.TimeoutFor("ContainerGKEClusters"), parsed.PollTimeFor("ContainerGKEClusters"); timeout != 30*time.Minute || pollTime != 30*time.Second {
+		t.Errorf("expected the overrides of ContainerGKEClusters, got: %v %v", timeout, pollTime)
+	}
+
+	parsed, err = parseFlags("--timeout", "10m", "--timeout", "ContainerGKEClusters=30m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Timeout != 10*time.Minute {
+		t.Errorf("expected the global timeout to be 10m, got: %v", parsed.Timeout)
+	}
+}
+
+func TestConfigFromFlagsZero(t *testing.T) {
+	for _, flag := range []string{"--timeout", "--polltime"} {
+		if _, err := parseFlags(flag, "0s"); err == nil || !strings.Contains(err.Error(), "must be positive") {
+			t.Errorf("expected %v 0s to be rejected, got: %v", flag, err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/daemon"
+	"github.com/arehmandev/gcp-nuke/gcp"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"github.com/arehmandev/gcp-nuke/report"
+	"github.com/urfave/cli/v2"
+)
+
+// serveCommand - gcp-nuke serve, runs the schedules from the config file until interrupted
+func serveCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "serve",
+		Usage:     "Run continuously, cleaning up projects on the cron schedules from the config file",
+		UsageText: "e.g. gcp-nuke --config schedules.yaml serve --addr 127.0.0.1:8080",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Value: "127.0.0.1:8080",
+				Usage: "Address of the status api, which also serves prometheus metrics on /metrics",
+			},
+			&cli.StringFlag{
+				Name:    "api-token",
+				EnvVars: []string{"GCP_NUKE_API_TOKEN"},
+				Usage:   "Bearer token needed to start runs through the status api, without one runs can only be started by their schedules",
+			},
+			&cli.StringFlag{
+				Name:  "history-dir",
+				Value: "gcp-nuke-history",
+				Usage: "Directory to keep the run history in, one json file per run",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			if !c.IsSet("config") {
+				return fmt.Errorf("gcp-nuke serve needs a --config file with schedules")
+			}
+			ctx, cancel := context.WithCancel(gcp.Ctx)
+			defer cancel()
+			baseConfig, err := configFromFlags(c, ctx)
+			if err != nil {
+				return err
+			}
+			stopTracing, err := startTracing(c)
+			if err != nil {
+				return err
+			}
+			defer stopTracing()
+
+			history, err := daemon.NewHistory(c.String("history-dir"))
+			if err != nil {
+				return err
+			}
+			janitor, err := daemon.New(baseConfig, runProject, history)
+			if err != nil {
+				return err
+			}
+
+			mux := http.NewServeMux()
+			if c.String("api-token") == "" {
+				log.Println("[Info] No --api-token, starting runs through the status api is disabled")
+			}
+			mux.Handle("/", janitor.Handler(c.String("api-token")))
+			mux.Handle("/metrics", gcp.MetricsHandler())
+			server := &http.Server{Addr: c.String("addr"), Handler: mux}
+			serverErrs := make(chan error, 1)
+			go func() {
+				log.Printf("[Info] Serving the status api on %v", c.String("addr"))
+				serverErrs <- server.ListenAndServe()
+			}()
+
+			janitor.Start()
+			helpers.SetupCloseHandler(cancel)
+			select {
+			case err = <-serverErrs:
+			case <-ctx.Done():
+			}
+
+			// Runs in progress are cancelled, so they still notify and make it into the history
+			log.Println("[Info] Stopping, waiting for runs in progress to cancel")
+			cancel()
+			janitor.Stop()
+			if shutdownErr := server.Shutdown(context.Background()); shutdownErr != nil && err == nil {
+				err = shutdownErr
+			}
+			if err == http.ErrServerClosed {
+				err = nil
+			}
+			return err
+		},
+	}
+}
+
+// runProject - looks up the zones and regions of the project, then removes it
+func runProject(config config.Config) (*report.Report, error) {
+	clients := gcp.NewClients(config)
+	var err error
+	if config.Zones, err = gcp.GetZones(clients, config.Project); err != nil {
+		return nil, err
+	}
+	if config.Regions, err = gcp.GetRegions(clients, config.Project); err != nil {
+		return nil, err
+	}
+	return gcp.RemoveProjectReport(config, clients)
+}
@@ -2,6 +2,15 @@ package config
 
 import (
 	"context"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/pricing"
+	"gopkg.in/yaml.v2"
 )
 
 // Config -
@@ -9,8 +18,296 @@ type Config struct {
 	Project  string
 	Zones    []string
 	Regions  []string
-	Timeout  int
-	PollTime int
+	Timeout  time.Duration
+	PollTime time.Duration
 	Context  context.Context
 	DryRun   bool
+	// MaxConcurrency - maximum number of items deleted at once across all resource types
+	MaxConcurrency int
+	// RateLimit - maximum requests per second to each API (compute, container...)
+	RateLimit float64
+	// Resources - per resource type overrides, keyed by resource type name e.g. ContainerGKEClusters
+	Resources map[string]ResourceConfig
+	// CredentialsFile - service account or user credentials json, application default credentials are used when empty
+	CredentialsFile string
+	// ImpersonateServiceAccount - email of a service account to impersonate for every API call
+	ImpersonateServiceAccount string
+	// QuotaProject - project billed for API quota, instead of the project the credentials belong to
+	QuotaProject string
+	// Endpoint - overrides the host of every API e.g. a local fake server, each API keeps its usual base path
+	Endpoint string
+	// WithoutAuthentication - skips credentials entirely, only useful together with Endpoint
+	WithoutAuthentication bool
+	// Notifications - webhooks notified of the plan and the result of the run
+	Notifications []Notification
+	// Schedules - projects cleaned up on a schedule by gcp-nuke serve
+	Schedules []Schedule
+	// TTLLabels - only delete items past the expiry in the first of these labels, e.g. expires-at=2026-10-20 or ttl=48h
+	TTLLabels []string
+	// TTLDefault - lifetime of items without a ttl label in TTLLabels mode, 0 leaves them alone
+	TTLDefault time.Duration
+	// OwnerLabels - labels holding the owner of an item, the first one set wins, defaults to owner and team
+	OwnerLabels []string
+	// Owners - only delete items of these owners, every owner when empty
+	Owners []string
+	// NamePatterns - only delete items with a name matching one of these, every item when empty
+	NamePatterns []*regexp.Regexp
+	// EmailPatterns - only delete service accounts and their keys with an email matching one of these, every one when empty
+	EmailPatterns []*regexp.Regexp
+	// AuditLog - export of the admin activity audit logs, to find the creator of items without an owner label
+	AuditLog string
+	// Creators - creators from AuditLog keyed by collection/name, loaded at the start of every run
+	Creators map[string]string
+	// PriceTable - yaml or json file updating the bundled price table used for cost estimates
+	PriceTable string
+	// Prices - the price table, loaded from PriceTable at the start of every run, the bundled one when nil
+	Prices *pricing.Table
+	// PlanFile - the plan is written to this file as json, once every resource type is listed
+	PlanFile string
+	// SnapshotDisks - snapshot every disk before deleting it, along with the disks of deleted instances
+	SnapshotDisks bool
+	// SnapshotSkipLabel - disks with this label are deleted without a snapshot, defaults to no-snapshot
+	SnapshotSkipLabel string
+	// DisableDeletionProtection - turn off the deletion protection of Cloud SQL instances so they can be deleted, otherwise they are reported and left
+	DisableDeletionProtection bool
+	// IncludeDefaultServiceAccounts - also delete the default compute and App Engine service accounts, which are otherwise kept
+	IncludeDefaultServiceAccounts bool
+}
+
+// ResourceConfig - overrides for a single resource type, zero values fall back to the global setting
+type ResourceConfig struct {
+	Timeout  time.Duration
+	PollTime time.Duration
+}
+
+// Notification - a webhook notified of run events, see the notify package
+type Notification struct {
+	URL string `yaml:"url"`
+	// Format - webhook for a json summary, or slack for a slack compatible message
+	Format string `yaml:"format"`
+	// Template - text/template for the message, a summary of the run is used when empty
+	Template string `yaml:"template"`
+	// Events - any of plan, completed and failed, defaults to completed and failed
+	Events []string `yaml:"events"`
+}
+
+// Schedule - a project cleaned up on a cron schedule by gcp-nuke serve, with its own policy
+type Schedule struct {
+	// Name - identifies the schedule in the run history and status api, defaults to the project
+	Name    string
+	Project string
+	// Cron - standard 5 field cron expression e.g. "0 2 * * *", or a descriptor such as @daily or @every 6h
+	Cron   string
+	DryRun bool
+	// Timeout - overrides the global timeout for runs of this schedule
+	Timeout time.Duration
+}
+
+// fileConfig - layout of the --config file
+type fileConfig struct {
+	Timeout       duration                      `yaml:"timeout"`
+	PollTime      duration                      `yaml:"polltime"`
+	Resources     map[string]fileResourceConfig `yaml:"resources"`
+	Notifications []Notification                `yaml:"notifications"`
+	Schedules     []fileSchedule                `yaml:"schedules"`
+	TTL           fileTTL                       `yaml:"ttl"`
+	Owners        fileOwners                    `yaml:"owners"`
+}
+
+type fileOwners struct {
+	Labels   []string `yaml:"labels"`
+	Only     []string `yaml:"only"`
+	AuditLog string   `yaml:"auditlog"`
+}
+
+type fileTTL struct {
+	Labels  []string `yaml:"labels"`
+	Default string   `yaml:"default"`
+}
+
+type fileSchedule struct {
+	Name    string   `yaml:"name"`
+	Project string   `yaml:"project"`
+	Cron    string   `yaml:"cron"`
+	DryRun  bool     `yaml:"dryrun"`
+	Timeout duration `yaml:"timeout"`
+}
+
+type fileResourceConfig struct {
+	Timeout  duration `yaml:"timeout"`
+	PollTime duration `yaml:"polltime"`
+}
+
+// duration - a yaml duration such as "10m", plain numbers are read as seconds
+type duration time.Duration
+
+// UnmarshalYAML -
+func (d *duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	value := ""
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	parsed, err := parseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+// LoadFile - reads the timeouts, poll times, notifications, schedules, ttl labels and owners from a yaml config file
+func (c *Config) LoadFile(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	file := fileConfig{}
+	if err := yaml.UnmarshalStrict(content, &file); err != nil {
+		return fmt.Errorf("invalid config file %v: %v", path, err)
+	}
+
+	if file.Timeout > 0 {
+		c.Timeout = time.Duration(file.Timeout)
+	}
+	if file.PollTime > 0 {
+		c.PollTime = time.Duration(file.PollTime)
+	}
+	for resourceName, resourceConfig := range file.Resources {
+		resourceConfig := resourceConfig
+		c.setResourceConfig(resourceName, func(r *ResourceConfig) {
+			r.Timeout = time.Duration(resourceConfig.Timeout)
+			r.PollTime = time.Duration(resourceConfig.PollTime)
+		})
+	}
+	c.Notifications = append(c.Notifications, file.Notifications...)
+	c.TTLLabels = append(c.TTLLabels, file.TTL.Labels...)
+	c.OwnerLabels = append(c.OwnerLabels, file.Owners.Labels...)
+	c.Owners = append(c.Owners, file.Owners.Only...)
+	// --audit-log takes precedence
+	if c.AuditLog == "" {
+		c.AuditLog = file.Owners.AuditLog
+	}
+	if file.TTL.Default != "" {
+		if c.TTLDefault, err = ParseTTL(file.TTL.Default); err != nil {
+			return fmt.Errorf("invalid config file %v: ttl default: %v", path, err)
+		}
+	}
+	for _, schedule := range file.Schedules {
+		if schedule.Project == "" || schedule.Cron == "" {
+			return fmt.Errorf("invalid config file %v: schedules need a project and a cron expression", path)
+		}
+		if schedule.Name == "" {
+			schedule.Name = schedule.Project
+		}
+		c.Schedules = append(c.Schedules, Schedule{
+			Name:    schedule.Name,
+			Project: schedule.Project,
+			Cron:    schedule.Cron,
+			DryRun:  schedule.DryRun,
+			Timeout: time.Duration(schedule.Timeout),
+		})
+	}
+	return nil
+}
+
+// SetTimeouts - parses --timeout values, either a default duration or ResourceType=duration
+func (c *Config) SetTimeouts(values []string) error {
+	return parseResourceValues(values, func(resourceName string, value time.Duration) {
+		if resourceName == "" {
+			c.Timeout = value
+			return
+		}
+		c.setResourceConfig(resourceName, func(r *ResourceConfig) {
+			r.Timeout = value
+		})
+	})
+}
+
+// SetPollTimes - parses --polltime values, either a default duration or ResourceType=duration
+func (c *Config) SetPollTimes(values []string) error {
+	return parseResourceValues(values, func(resourceName string, value time.Duration) {
+		if resourceName == "" {
+			c.PollTime = value
+			return
+		}
+		c.setResourceConfig(resourceName, func(r *ResourceConfig) {
+			r.PollTime = value
+		})
+	})
+}
+
+// TimeoutFor - timeout for the resource type, falling back to Timeout
+func (c Config) TimeoutFor(resourceName string) time.Duration {
+	if override := c.Resources[resourceName].Timeout; override > 0 {
+		return override
+	}
+	return c.Timeout
+}
+
+// PollTimeFor - poll time for the resource type, falling back to PollTime
+func (c Config) PollTimeFor(resourceName string) time.Duration {
+	if override := c.Resources[resourceName].PollTime; override > 0 {
+		return override
+	}
+	return c.PollTime
+}
+
+func (c *Config) setResourceConfig(resourceName string, update func(r *ResourceConfig)) {
+	if c.Resources == nil {
+		c.Resources = make(map[string]ResourceConfig)
+	}
+	resourceConfig := c.Resources[resourceName]
+	update(&resourceConfig)
+	c.Resources[resourceName] = resourceConfig
+}
+
+// parseResourceValues - splits values of the form "10m" or "ResourceType=10m"
+func parseResourceValues(values []string, set func(resourceName string, value time.Duration)) error {
+	for _, value := range values {
+		resourceName := ""
+		durationValue := value
+		if split := strings.SplitN(value, "=", 2); len(split) == 2 {
+			resourceName, durationValue = split[0], split[1]
+		}
+		parsed, err := parseDuration(durationValue)
+		if err != nil {
+			return fmt.Errorf("invalid value %q, expected a duration or ResourceType=duration: %v", value, err)
+		}
+		set(resourceName, parsed)
+	}
+	return nil
+}
+
+// parseDuration - parses durations such as "90s" or "10m", plain numbers are seconds to keep older configs working
+func parseDuration(value string) (time.Duration, error) {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, atoiErr := strconv.Atoi(value)
+		if atoiErr != nil {
+			return 0, err
+		}
+		parsed = time.Duration(seconds) * time.Second
+	}
+	if parsed <= 0 {
+		return 0, fmt.Errorf("duration %q must be positive", value)
+	}
+	return parsed, nil
+}
+
+// ParseTTL - a ttl such as 48h or 90m, or a number of days such as 7d
+func ParseTTL(value string) (time.Duration, error) {
+	if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && strings.HasSuffix(value, "d") {
+		if days <= 0 {
+			return 0, fmt.Errorf("ttl %q must be positive", value)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ttl %q, expected a duration such as 48h or a number of days such as 7d", value)
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("ttl %q must be positive", value)
+	}
+	return ttl, nil
 }
@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Handler - the status api
+//
+//	GET  /status                  every schedule, its next run and whether its project is running
+//	GET  /runs?limit=20           run history, most recent first
+//	GET  /runs/{id}               a single run, including its report
+//	POST /schedules/{name}/run    starts a run of the schedule right away
+//
+// Starting a run needs the token as a bearer token, without a token it's disabled
+func (d *Daemon) Handler(token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"schedules": d.Statuses()})
+	})
+
+	mux.HandleFunc("/runs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		limit := 20
+		if value := r.URL.Query().Get("limit"); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil || parsed < 0 {
+				writeError(w, http.StatusBadRequest, "limit must be a positive number, or 0 for all runs")
+				return
+			}
+			limit = parsed
+		}
+		records, err := d.history.List(limit)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"runs": records})
+	})
+
+	mux.HandleFunc("/runs/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		record, err := d.history.Get(strings.TrimPrefix(r.URL.Path, "/runs/"))
+		if os.IsNotExist(err) {
+			writeError(w, http.StatusNotFound, "run not found")
+			return
+		}
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, record)
+	})
+
+	mux.HandleFunc("/schedules/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/schedules/")
+		if !strings.HasSuffix(name, "/run") {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		if r.Method != http.MethodPost {
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		if token == "" {
+			writeError(w, http.StatusForbidden, "starting runs is disabled, gcp-nuke serve needs an --api-token")
+			return
+		}
+		if !hasBearerToken(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		name = strings.TrimSuffix(name, "/run")
+		if !d.hasSchedule(name) {
+			writeError(w, http.StatusNotFound, "schedule not found")
+			return
+		}
+		record, err := d.Trigger(name)
+		if err != nil {
+			writeJSON(w, http.StatusConflict, map[string]interface{}{"error": err.Error(), "run": record})
+			return
+		}
+		writeJSON(w, http.StatusAccepted, record)
+	})
+	return mux
+}
+
+func (d *Daemon) hasSchedule(name string) bool {
+	for _, schedule := range d.config.Schedules {
+		if schedule.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBearerToken - whether the request is authorized with the token, compared in constant time
+func hasBearerToken(r *http.Request, token string) bool {
+	authorization := r.Header.Get("Authorization")
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authorization, "Bearer ")), []byte(token)) == 1
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,221 @@
+// Package daemon - runs cleanups on cron schedules for gcp-nuke serve, with a run history and a status api
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/report"
+	"github.com/robfig/cron/v3"
+)
+
+// Runner - runs a cleanup of the project in the config, gcp.RemoveProjectReport once the clients are set up
+type Runner func(config config.Config) (*report.Report, error)
+
+// Daemon - runs every schedule from the config, never running the same project twice at once
+type Daemon struct {
+	config  config.Config
+	runner  Runner
+	history *History
+	cron    *cron.Cron
+	entries map[string]cron.EntryID
+
+	mutex sync.Mutex
+	// running - schedule name of the run in progress, per project
+	running map[string]string
+	// stopped - set by Stop, no run is started after it
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// New - validates the schedules of the config, which is the base config of every run
+func New(baseConfig config.Config, runner Runner, history *History) (*Daemon, error) {
+	if len(baseConfig.Schedules) == 0 {
+		return nil, fmt.Errorf("no schedules in the config file")
+	}
+	d := &Daemon{
+		config:  baseConfig,
+		runner:  runner,
+		history: history,
+		cron:    cron.New(),
+		entries: make(map[string]cron.EntryID),
+		running: make(map[string]string),
+	}
+	for _, schedule := range baseConfig.Schedules {
+		schedule := schedule
+		if strings.ContainsAny(schedule.Name, `/\`) {
+			return nil, fmt.Errorf("invalid schedule name %q", schedule.Name)
+		}
+		if _, exists := d.entries[schedule.Name]; exists {
+			return nil, fmt.Errorf("duplicate schedule name %q", schedule.Name)
+		}
+		entryID, err := d.cron.AddFunc(schedule.Cron, func() {
+			d.Run(schedule)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q for schedule %v: %v", schedule.Cron, schedule.Name, err)
+		}
+		d.entries[schedule.Name] = entryID
+	}
+	return d, nil
+}
+
+// Start - starts running the schedules in the background
+func (d *Daemon) Start() {
+	for _, schedule := range d.config.Schedules {
+		log.Printf("[Info] Schedule %v: project %v at %q (dry-run: %v)", schedule.Name, schedule.Project, schedule.Cron, schedule.DryRun)
+	}
+	d.cron.Start()
+}
+
+// Stop - stops scheduling new runs, and waits for the runs in progress
+func (d *Daemon) Stop() {
+	d.mutex.Lock()
+	d.stopped = true
+	d.mutex.Unlock()
+	<-d.cron.Stop().Done()
+	d.wg.Wait()
+}
+
+// Trigger - starts a run of the schedule right away in the background, unless its project is already running
+func (d *Daemon) Trigger(name string) (Record, error) {
+	for _, schedule := range d.config.Schedules {
+		if schedule.Name != name {
+			continue
+		}
+		record, started := d.start(schedule)
+		if !started {
+			return record, errors.New(record.Error)
+		}
+		go d.finish(schedule, record)
+		return record, nil
+	}
+	return Record{}, fmt.Errorf("unknown schedule %q", name)
+}
+
+// Run - runs the schedule and waits for it to finish, skipping it if its project is already running
+func (d *Daemon) Run(schedule config.Schedule) Record {
+	record, started := d.start(schedule)
+	if !started {
+		return record
+	}
+	return d.finish(schedule, record)
+}
+
+// start - claims the project for the schedule, or records the run as skipped when another run has it or the daemon is stopping
+func (d *Daemon) start(schedule config.Schedule) (record Record, started bool) {
+	start := time.Now()
+	record = Record{
+		ID:       newRecordID(schedule.Name, start),
+		Schedule: schedule.Name,
+		Project:  schedule.Project,
+		DryRun:   schedule.DryRun,
+		Start:    start,
+		Status:   StatusRunning,
+	}
+
+	// Claimed under the mutex along with the stopped check, so Stop never waits while a run can still be added
+	d.mutex.Lock()
+	runningSchedule, running := d.running[schedule.Project]
+	stopped := d.stopped
+	if !running && !stopped {
+		d.running[schedule.Project] = schedule.Name
+		d.wg.Add(1)
+	}
+	d.mutex.Unlock()
+
+	switch {
+	case stopped:
+		log.Printf("[Skipping] Schedule %v, gcp-nuke serve is stopping", schedule.Name)
+		record.End = start
+		record.Status = StatusSkipped
+		record.Error = "gcp-nuke serve is stopping"
+	case running:
+		log.Printf("[Skipping] Schedule %v, project %v is still being cleaned up by schedule %v", schedule.Name, schedule.Project, runningSchedule)
+		record.End = start
+		record.Status = StatusSkipped
+		record.Error = fmt.Sprintf("project still being cleaned up by schedule %v", runningSchedule)
+	}
+	d.save(record)
+	return record, !running && !stopped
+}
+
+// finish - runs the cleanup claimed by start, and releases the project
+func (d *Daemon) finish(schedule config.Schedule, record Record) Record {
+	defer func() {
+		d.mutex.Lock()
+		delete(d.running, schedule.Project)
+		d.mutex.Unlock()
+		d.wg.Done()
+	}()
+
+	log.Printf("[Info] Schedule %v starting run %v of project %v", schedule.Name, record.ID, schedule.Project)
+	runReport, err := d.runner(d.runConfig(schedule))
+	record.End = time.Now()
+	record.Report = runReport
+	record.Status = StatusSucceeded
+	if err != nil {
+		record.Status = StatusFailed
+		record.Error = err.Error()
+		log.Printf("[Error] Schedule %v run %v failed: %v", schedule.Name, record.ID, err)
+	} else {
+		log.Printf("[Info] Schedule %v run %v succeeded (%v)", schedule.Name, record.ID, record.End.Sub(record.Start).Round(time.Second))
+	}
+	d.save(record)
+	return record
+}
+
+// runConfig - the base config with the project and policy of the schedule
+func (d *Daemon) runConfig(schedule config.Schedule) config.Config {
+	runConfig := d.config
+	runConfig.Project = schedule.Project
+	runConfig.DryRun = schedule.DryRun
+	if schedule.Timeout > 0 {
+		runConfig.Timeout = schedule.Timeout
+	}
+	// Zones and regions differ per project, so the runner looks them up
+	runConfig.Zones = nil
+	runConfig.Regions = nil
+	return runConfig
+}
+
+func (d *Daemon) save(record Record) {
+	if err := d.history.Save(record); err != nil {
+		log.Printf("[Error] Saving run %v to the history: %v", record.ID, err)
+	}
+}
+
+// Status - a schedule and its next run
+type Status struct {
+	Name    string    `json:"name"`
+	Project string    `json:"project"`
+	Cron    string    `json:"cron"`
+	DryRun  bool      `json:"dryRun"`
+	Next    time.Time `json:"next"`
+	// Running - the project is being cleaned up, by this or another schedule
+	Running bool `json:"running"`
+}
+
+// Statuses - every schedule, in config order
+func (d *Daemon) Statuses() []Status {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	statuses := []Status{}
+	for _, schedule := range d.config.Schedules {
+		_, running := d.running[schedule.Project]
+		statuses = append(statuses, Status{
+			Name:    schedule.Name,
+			Project: schedule.Project,
+			Cron:    schedule.Cron,
+			DryRun:  schedule.DryRun,
+			Next:    d.cron.Entry(d.entries[schedule.Name]).Next,
+			Running: running,
+		})
+	}
+	return statuses
+}
@@ -0,0 +1,284 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/report"
+)
+
+// blockingRunner - runner which waits for release, recording the config of every run
+type blockingRunner struct {
+	started chan config.Config
+	release chan error
+}
+
+func newBlockingRunner() *blockingRunner {
+	return &blockingRunner{
+		started: make(chan config.Config, 10),
+		release: make(chan error, 10),
+	}
+}
+
+func (r *blockingRunner) run(runConfig config.Config) (*report.Report, error) {
+	r.started <- runConfig
+	err := <-r.release
+	runReport := report.New(runConfig.Project, runConfig.DryRun)
+	runReport.Finish(err)
+	return runReport, err
+}
+
+func newTestDaemon(t *testing.T, runner Runner, schedules ...config.Schedule) *Daemon {
+	history, err := NewHistory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseConfig := config.Config{
+		Context:   context.Background(),
+		Timeout:   time.Minute,
+		Schedules: schedules,
+	}
+	d, err := New(baseConfig, runner, history)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestRunSkipsOverlappingProject(t *testing.T) {
+	runner := newBlockingRunner()
+	d := newTestDaemon(t, runner.run,
+		config.Schedule{Name: "nightly", Project: "sandbox", Cron: "@daily", Timeout: time.Hour},
+		config.Schedule{Name: "hourly", Project: "sandbox", Cron: "@hourly", DryRun: true},
+	)
+
+	first, err := d.Trigger("nightly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	runConfig := <-runner.started
+	if runConfig.Project != "sandbox" || runConfig.Timeout != time.Hour || runConfig.DryRun {
+		t.Errorf("expected the policy of the schedule in the run config, got: %+v", runConfig)
+	}
+
+	skipped := d.Run(config.Schedule{Name: "hourly", Project: "sandbox", Cron: "@hourly", DryRun: true})
+	if skipped.Status != StatusSkipped {
+		t.Errorf("expected the overlapping run to be skipped, got: %+v", skipped)
+	}
+	if _, err := d.Trigger("nightly"); err == nil {
+		t.Error("expected triggering a running project to fail")
+	}
+	if statuses := d.Statuses(); !statuses[0].Running || !statuses[1].Running {
+		t.Errorf("expected both schedules of the project to show as running, got: %+v", statuses)
+	}
+
+	runner.release <- errors.New("resource deletion timed out")
+	d.Stop()
+
+	records, err := d.history.List(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := map[string]Record{}
+	for _, record := range records {
+		statuses[record.ID] = record
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected the failed run and two skipped runs, got: %+v", records)
+	}
+	if record := statuses[first.ID]; record.Status != StatusFailed || record.Error != "resource deletion timed out" || record.Report == nil {
+		t.Errorf("expected the first run to have failed with its report, got: %+v", record)
+	}
+	if record := statuses[skipped.ID]; record.Status != StatusSkipped {
+		t.Errorf("expected the skipped run in the history, got: %+v", record)
+	}
+}
+
+func TestScheduleRuns(t *testing.T) {
+	runner := newBlockingRunner()
+	d := newTestDaemon(t, runner.run, config.Schedule{Name: "sandbox", Project: "sandbox", Cron: "@every 1s"})
+	d.Start()
+	defer d.Stop()
+
+	select {
+	case runConfig := <-runner.started:
+		if runConfig.Project != "sandbox" {
+			t.Errorf("expected a run of sandbox, got: %v", runConfig.Project)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the schedule to run")
+	}
+	// Enough for a second run, in case the schedule fires again before it's stopped
+	runner.release <- nil
+	runner.release <- nil
+}
+
+func TestHistorySurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	history, err := NewHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	for i, name := range []string{"older", "newer"} {
+		record := Record{ID: newRecordID(name, start.Add(time.Duration(i)*time.Second)), Schedule: name, Status: StatusSucceeded}
+		if err := history.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	restarted, err := NewHistory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := restarted.List(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Schedule != "newer" {
+		t.Errorf("expected only the most recent run, got: %+v", records)
+	}
+	if _, err := restarted.Get("../secrets"); err == nil {
+		t.Error("expected run ids with paths to be rejected")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	runner := newBlockingRunner()
+	d := newTestDaemon(t, runner.run, config.Schedule{Name: "sandbox", Project: "sandbox", Cron: "0 2 * * *"})
+	d.Start()
+	defer d.Stop()
+	server := httptest.NewServer(d.Handler("secret"))
+	defer server.Close()
+
+	triggered := Record{}
+	if status := postRun(t, server.URL+"/schedules/sandbox/run", "secret", &triggered); status != http.StatusAccepted || triggered.Status != StatusRunning {
+		t.Fatalf("expected the run to start, got %v: %+v", status, triggered)
+	}
+	<-runner.started
+
+	status := struct{ Schedules []Status }{}
+	getJSON(t, server.URL+"/status", http.StatusOK, &status)
+	if len(status.Schedules) != 1 || !status.Schedules[0].Running || status.Schedules[0].Next.Hour() != 2 {
+		t.Errorf("expected the running schedule with its next run at 2am, got: %+v", status)
+	}
+
+	runner.release <- nil
+	for i := 0; i < 100; i++ {
+		if statuses := d.Statuses(); !statuses[0].Running {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	runs := struct{ Runs []Record }{}
+	getJSON(t, server.URL+"/runs", http.StatusOK, &runs)
+	if len(runs.Runs) != 1 || runs.Runs[0].Status != StatusSucceeded {
+		t.Errorf("expected the run to have succeeded, got: %+v", runs)
+	}
+	run := Record{}
+	getJSON(t, server.URL+"/runs/"+triggered.ID, http.StatusOK, &run)
+	if run.Report == nil || run.Report.Project != "sandbox" {
+		t.Errorf("expected the run with its report, got: %+v", run)
+	}
+
+	getJSON(t, server.URL+"/runs/20060102T150405.000Z-missing", http.StatusNotFound, &struct{}{})
+	if status := postRun(t, server.URL+"/schedules/missing/run", "secret", &struct{}{}); status != http.StatusNotFound {
+		t.Errorf("expected unknown schedules to be not found, got: %v", status)
+	}
+}
+
+func TestHandlerToken(t *testing.T) {
+	runner := newBlockingRunner()
+	d := newTestDaemon(t, runner.run, config.Schedule{Name: "sandbox", Project: "sandbox", Cron: "0 2 * * *"})
+	defer d.Stop()
+
+	server := httptest.NewServer(d.Handler("secret"))
+	defer server.Close()
+	for _, token := range []string{"", "wrong"} {
+		if status := postRun(t, server.URL+"/schedules/sandbox/run", token, &struct{}{}); status != http.StatusUnauthorized {
+			t.Errorf("expected starting a run with token %q to be unauthorized, got: %v", token, status)
+		}
+	}
+
+	withoutToken := httptest.NewServer(d.Handler(""))
+	defer withoutToken.Close()
+	if status := postRun(t, withoutToken.URL+"/schedules/sandbox/run", "", &struct{}{}); status != http.StatusForbidden {
+		t.Errorf("expected starting runs to be disabled without a token, got: %v", status)
+	}
+	getJSON(t, withoutToken.URL+"/status", http.StatusOK, &struct{}{})
+
+	select {
+	case <-runner.started:
+		t.Error("expected no run to start")
+	default:
+	}
+}
+
+func TestTriggerAfterStop(t *testing.T) {
+	runner := newBlockingRunner()
+	d := newTestDaemon(t, runner.run, config.Schedule{Name: "sandbox", Project: "sandbox", Cron: "0 2 * * *"})
+	d.Start()
+	d.Stop()
+
+	record, err := d.Trigger("sandbox")
+	if err == nil || record.Status != StatusSkipped {
+		t.Errorf("expected no run to start once stopped, got %v: %+v", err, record)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	history, err := NewHistory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, schedules := range [][]config.Schedule{
+		{},
+		{{Name: "sandbox", Project: "sandbox", Cron: "every night"}},
+		{{Name: "sandbox", Project: "a", Cron: "@daily"}, {Name: "sandbox", Project: "b", Cron: "@daily"}},
+		{{Name: "a/b", Project: "a", Cron: "@daily"}},
+	} {
+		if _, err := New(config.Config{Schedules: schedules}, nil, history); err == nil {
+			t.Errorf("expected an error for %+v", schedules)
+		}
+	}
+}
+
+// postRun - starts a run through the api with the bearer token, if any
+func postRun(t *testing.T, url, token string, body interface{}) int {
+	req, err := http.NewRequest(http.MethodPost, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(body); err != nil {
+		t.Error(err)
+	}
+	return resp.StatusCode
+}
+
+func getJSON(t *testing.T, url string, expectedStatus int, body interface{}) {
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != expectedStatus {
+		t.Errorf("expected %v from %v, got: %v", expectedStatus, url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(body); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,112 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/report"
+)
+
+// Run statuses
+const (
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	// StatusSkipped - the schedule fired while the previous run of the project was still going
+	StatusSkipped = "skipped"
+)
+
+// Record - a single run of a schedule
+type Record struct {
+	ID       string         `json:"id"`
+	Schedule string         `json:"schedule"`
+	Project  string         `json:"project"`
+	DryRun   bool           `json:"dryRun"`
+	Start    time.Time      `json:"start"`
+	End      time.Time      `json:"end"`
+	Status   string         `json:"status"`
+	Error    string         `json:"error,omitempty"`
+	Report   *report.Report `json:"report,omitempty"`
+}
+
+// History - run records kept as one json file per run, so they survive restarts of the daemon
+type History struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+// NewHistory - keeps the history in dir, creating it if needed
+func NewHistory(dir string) (*History, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &History{dir: dir}, nil
+}
+
+// newRecordID - sortable and unique per schedule, a skipped run can be recorded in the same millisecond as the run it overlaps
+func newRecordID(scheduleName string, start time.Time) string {
+	return start.UTC().Format("20060102T150405.000000000Z") + "-" + scheduleName
+}
+
+// Save - writes the record, replacing any earlier version of it
+func (h *History) Save(record Record) error {
+	content, err := json.MarshalIndent(record, "", "  ")
+	if err != nil {
+		return err
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	return ioutil.WriteFile(h.path(record.ID), content, 0644)
+}
+
+// Get - the record with the id
+func (h *History) Get(id string) (Record, error) {
+	record := Record{}
+	if strings.ContainsAny(id, `/\`) {
+		return record, fmt.Errorf("invalid run id %q", id)
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	content, err := ioutil.ReadFile(h.path(id))
+	if err != nil {
+		return record, err
+	}
+	err = json.Unmarshal(content, &record)
+	return record, err
+}
+
+// List - the most recent records first, up to limit, or all of them when limit is 0
+func (h *History) List(limit int) ([]Record, error) {
+	h.mutex.Lock()
+	files, err := filepath.Glob(filepath.Join(h.dir, "*.json"))
+	h.mutex.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	// Ids start with the time, so the file names sort by time
+	sort.Sort(sort.Reverse(sort.StringSlice(files)))
+	if limit > 0 && len(files) > limit {
+		files = files[:limit]
+	}
+
+	records := []Record{}
+	for _, file := range files {
+		record, err := h.Get(strings.TrimSuffix(filepath.Base(file), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func (h *History) path(id string) string {
+	return filepath.Join(h.dir, id+".json")
+}
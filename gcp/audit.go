@@ -0,0 +1,133 @@
+package gcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// auditCollections - the collection in audit log resource names, per resource type
+var auditCollections = map[string]string{
+	"BigQueryDatasets":             "datasets",
+	"BigQueryReservations":         "reservations",
+	"BigQueryTransferConfigs":      "transferConfigs",
+	"CloudFunctions":               "functions",
+	"CloudRunJobs":                 "jobs",
+	"CloudRunServices":             "services",
+	"ComputeAddresses":             "addresses",
+	"ComputeBackendBuckets":        "backendBuckets",
+	"ComputeBackendServices":       "backendServices",
+	"ComputeDisks":                 "disks",
+	"ComputeFirewalls":             "firewalls",
+	"ComputeForwardingRules":       "forwardingRules",
+	"ComputeGlobalAddresses":       "addresses",
+	"ComputeGlobalForwardingRules": "forwardingRules",
+	"ComputeHealthChecks":          "healthChecks",
+	"ComputeInstanceGroupsRegion":  "instanceGroupManagers",
+	"ComputeInstanceGroupsZone":    "instanceGroupManagers",
+	"ComputeInstanceTemplates":     "instanceTemplates",
+	"ComputeInstances":             "instances",
+	"ComputeNetworks":              "networks",
+	"ComputeRegionAutoScalers":     "autoscalers",
+	"ComputeRouters":               "routers",
+	"ComputeSubnetworks":           "subnetworks",
+	"ComputeTargetHTTPProxies":     "targetHttpProxies",
+	"ComputeTargetHTTPSProxies":    "targetHttpsProxies",
+	"ComputeTargetPools":           "targetPools",
+	"ComputeTargetSSLProxies":      "targetSslProxies",
+	"ComputeTargetTCPProxies":      "targetTcpProxies",
+	"ComputeURLMaps":               "urlMaps",
+	"ComputeVPNGateways":           "vpnGateways",
+	"ComputeVPNTunnels":            "vpnTunnels",
+	"ComputeZoneAutoScalers":       "autoscalers",
+	"ContainerGKEClusters":         "clusters",
+	"DNSManagedZones":              "managedZones",
+	"DNSPolicies":                  "policies",
+	"IAMServiceAccountKeys":        "keys",
+	"PubSubSchemas":                "schemas",
+	"PubSubSnapshots":              "snapshots",
+	"PubSubSubscriptions":          "subscriptions",
+	"PubSubTopics":                 "topics",
+	"SQLInstances":                 "instances",
+	"StorageBuckets":               "buckets",
+	"VPCAccessConnectors":          "connectors",
+}
+
+// auditLogEntry - the fields of a Cloud Audit Logs entry needed to find who created a resource
+type auditLogEntry struct {
+	Timestamp    time.Time `json:"timestamp"`
+	ProtoPayload struct {
+		MethodName         string `json:"methodName"`
+		ResourceName       string `json:"resourceName"`
+		AuthenticationInfo struct {
+			PrincipalEmail string `json:"principalEmail"`
+		} `json:"authenticationInfo"`
+	} `json:"protoPayload"`
+}
+
+// loadAuditLog - creators of resources, keyed by collection/name e.g. disks/scratch, from an export of the admin activity audit logs
+// The export is either a json array, as written by gcloud logging read --format json, or one entry per line, as written by log sinks
+func loadAuditLog(path string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []auditLogEntry{}
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("[")) {
+		if err := json.Unmarshal(content, &entries); err != nil {
+			return nil, fmt.Errorf("invalid audit log %v: %v", path, err)
+		}
+	} else {
+		decoder := json.NewDecoder(bytes.NewReader(content))
+		for {
+			entry := auditLogEntry{}
+			if err := decoder.Decode(&entry); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("invalid audit log %v: %v", path, err)
+			}
+			entries = append(entries, entry)
+		}
+	}
+
+	creators := make(map[string]string)
+	created := make(map[string]time.Time)
+	for _, entry := range entries {
+		method := strings.ToLower(entry.ProtoPayload.MethodName)
+		creator := entry.ProtoPayload.AuthenticationInfo.PrincipalEmail
+		if creator == "" || !(strings.HasSuffix(method, ".insert") || strings.Contains(method, "create")) {
+			continue
+		}
+		key := auditKey(entry.ProtoPayload.ResourceName)
+		// A name can be reused once the resource is deleted, the latest creation wins
+		if entry.Timestamp.Before(created[key]) {
+			continue
+		}
+		creators[key] = creator
+		created[key] = entry.Timestamp
+	}
+	return creators, nil
+}
+
+// creatorOf - creator of the item in the audit log, empty when unknown
+func creatorOf(creators map[string]string, resourceName, itemName string) string {
+	collection, exists := auditCollections[resourceName]
+	if !exists || len(creators) == 0 {
+		return ""
+	}
+	return creators[collection+"/"+itemName[strings.LastIndex(itemName, "/")+1:]]
+}
+
+// auditKey - the last two segments of the resource name e.g. projects/p/zones/z/disks/scratch gives disks/scratch
+func auditKey(resourceName string) string {
+	segments := strings.Split(resourceName, "/")
+	if len(segments) < 2 {
+		return resourceName
+	}
+	return strings.Join(segments[len(segments)-2:], "/")
+}
@@ -0,0 +1,123 @@
+package gcp
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/bigquery/v2"
+)
+
+// bigqueryMultiRegions - the multi-region locations of BigQuery, which have no compute region
+var bigqueryMultiRegions = []string{"US", "EU"}
+
+// BigQueryDatasets - datasets of every location, deleted along with their tables, views and routines
+type BigQueryDatasets struct {
+	serviceClient *bigquery.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &BigQueryDatasets{}
+	})
+}
+
+// Name - Name of the resourceLister for BigQueryDatasets
+func (c *BigQueryDatasets) Name() string {
+	return "BigQueryDatasets"
+}
+
+// ToSlice - Name of the resourceLister for BigQueryDatasets
+func (c *BigQueryDatasets) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *BigQueryDatasets) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.BigQuery()
+	return err
+}
+
+// List - Returns a list of all BigQueryDatasets, the list is the same in every location
+// The list has no creation time, so each dataset is only fetched for it when --ttl-label needs it
+func (c *BigQueryDatasets) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	datasetListCall := c.serviceClient.Datasets.List(c.base.config.Project)
+	err := datasetListCall.Pages(ctx, func(datasetList *bigquery.DatasetList) error {
+		for _, dataset := range datasetList.Datasets {
+			datasetID := dataset.DatasetReference.DatasetId
+			created := ""
+			if c.base.needsCreationTime() {
+				datasetDetails, err := c.serviceClient.Datasets.Get(c.base.config.Project, datasetID).Context(ctx).Do()
+				if err != nil {
+					return err
+				}
+				created = time.Unix(0, datasetDetails.CreationTime*int64(time.Millisecond)).UTC().Format(time.RFC3339)
+			}
+			if c.base.skip(c.Name(), listedItem{name: datasetID, labels: dataset.Labels, created: created}) {
+				continue
+			}
+			c.resourceMap.Store(datasetID, dataset.Location)
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *BigQueryDatasets) Dependencies() []string {
+	// Transfer configs would keep writing into their destination dataset
+	a := BigQueryTransferConfigs{}
+	return []string{a.Name()}
+}
+
+// Remove -
+func (c *BigQueryDatasets) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		datasetID := key.(string)
+		location := value.(string)
+
+		// Parallel dataset deletion
+		errs.Go(datasetID, location, func(ctx context.Context) error {
+			deleteCall := c.serviceClient.Datasets.Delete(c.base.config.Project, datasetID).DeleteContents(true)
+			if err := deleteCall.Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v location: %v]", datasetID, c.Name(), c.base.config.Project, location)
+			c.resourceMap.Delete(datasetID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
+
+// bigqueryLocations - the multi-regions and the regions of the project, the locations transfer configs and reservations are listed in
+func bigqueryLocations(regions []string) []string {
+	return append(append([]string{}, bigqueryMultiRegions...), regions...)
+}
@@ -0,0 +1,148 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"github.com/arehmandev/gcp-nuke/report"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/bigqueryreservation/v1"
+)
+
+// BigQueryReservations - slot reservations of every location, keyed by location/name
+// A reservation can't be deleted whilst projects are assigned to it, so its assignments are deleted first
+type BigQueryReservations struct {
+	serviceClient *bigqueryreservation.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &BigQueryReservations{}
+	})
+}
+
+// Name - Name of the resourceLister for BigQueryReservations
+func (c *BigQueryReservations) Name() string {
+	return "BigQueryReservations"
+}
+
+// ToSlice - Name of the resourceLister for BigQueryReservations
+func (c *BigQueryReservations) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *BigQueryReservations) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.BigQueryReservation()
+	return err
+}
+
+// List - Returns a list of all BigQueryReservations
+func (c *BigQueryReservations) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	for _, location := range bigqueryLocations(c.base.config.Regions) {
+		reservationListCall := c.serviceClient.Projects.Locations.Reservations.List("projects/" + c.base.config.Project + "/locations/" + location)
+		err := reservationListCall.Pages(ctx, func(reservationList *bigqueryreservation.ListReservationsResponse) error {
+			for _, reservation := range reservationList.Reservations {
+				reservationKey := regionalKey(location, keyName(reservation.Name))
+				// Reservations have no labels, only the creation time is there for --ttl-label
+				if c.base.skip(c.Name(), listedItem{name: reservationKey, created: reservation.CreationTime}) {
+					continue
+				}
+				c.resourceMap.Store(reservationKey, reservation.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			if c.base.apiDisabled(err) {
+				return c.ToSlice(), nil
+			}
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *BigQueryReservations) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *BigQueryReservations) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		reservationKey := key.(string)
+		reservationName := value.(string)
+		location := strings.Split(reservationName, "/")[3]
+
+		// Parallel reservation deletion
+		errs.Go(reservationKey, location, func(ctx context.Context) error {
+			others, err := c.removeAssignments(ctx, reservationName)
+			if err != nil {
+				return err
+			}
+			if len(others) > 0 {
+				// The reservation can't be deleted while other projects, folders or organizations are assigned to it, and they are out of scope
+				log.Printf("[Warning] Reservation %v is still assigned to %v", reservationKey, strings.Join(others, ", "))
+				if runReport := report.FromContext(ctx); runReport != nil {
+					runReport.AddNote(c.Name(), reservationKey, "assigned to "+strings.Join(others, ", "))
+				}
+				return errLeftInPlace
+			}
+			if _, err := c.serviceClient.Projects.Locations.Reservations.Delete(reservationName).Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v location: %v]", keyName(reservationKey), c.Name(), c.base.config.Project, location)
+			c.resourceMap.Delete(reservationKey)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
+
+// removeAssignments - deletes the assignments of the project to the reservation, and returns the assignees outside of it
+func (c *BigQueryReservations) removeAssignments(ctx context.Context, reservationName string) (others []string, err error) {
+	assignments := []string{}
+	assignmentListCall := c.serviceClient.Projects.Locations.Reservations.Assignments.List(reservationName)
+	err = assignmentListCall.Pages(ctx, func(assignmentList *bigqueryreservation.ListAssignmentsResponse) error {
+		for _, assignment := range assignmentList.Assignments {
+			if assignment.Assignee != "projects/"+c.base.config.Project {
+				others = append(others, assignment.Assignee)
+				continue
+			}
+			assignments = append(assignments, assignment.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, assignment := range assignments {
+		if _, err := c.serviceClient.Projects.Locations.Reservations.Assignments.Delete(assignment).Context(ctx).Do(); err != nil && !isNotFound(err) {
+			return nil, fmt.Errorf("deleting assignment %v: %v", assignment, err)
+		}
+		log.Printf("[Info] Removed assignment %v from reservation %v", keyName(assignment), keyName(reservationName))
+	}
+	return others, nil
+}
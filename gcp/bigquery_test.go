@@ -0,0 +1,117 @@
+package gcp
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/bigquerydatatransfer/v1"
+	"google.golang.org/api/bigqueryreservation/v1"
+)
+
+// TestRemoveProjectBigQuery - datasets of every location are deleted with their contents after the transfer configs writing into them,
+// reservations after their assignments, and datasets of other owners are kept
+func TestRemoveProjectBigQuery(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	project := "projects/" + testProject
+
+	server.Add("datasets", &bigquery.Dataset{DatasetReference: &bigquery.DatasetReference{DatasetId: "ci_scratch"}, Location: "US", Labels: map[string]string{"team": "ci"}, CreationTime: 1760000000000})
+	server.Add("datasets/ci_scratch/tables", &bigquery.Table{TableReference: &bigquery.TableReference{TableId: "events"}, Type: "TABLE"})
+	server.Add("datasets/ci_scratch/tables", &bigquery.Table{TableReference: &bigquery.TableReference{TableId: "daily"}, Type: "VIEW"})
+	server.Add("datasets/ci_scratch/routines", &bigquery.Routine{RoutineReference: &bigquery.RoutineReference{RoutineId: "normalise"}})
+	server.Add("datasets", &bigquery.Dataset{DatasetReference: &bigquery.DatasetReference{DatasetId: "ci_eu"}, Location: testRegion, Labels: map[string]string{"team": "ci"}, CreationTime: 1760000000000})
+	server.Add("datasets", &bigquery.Dataset{DatasetReference: &bigquery.DatasetReference{DatasetId: "warehouse"}, Location: "EU", Labels: map[string]string{"team": "analytics"}, CreationTime: 1760000000000})
+	server.Add("locations/US/transferConfigs", &bigquerydatatransfer.TransferConfig{Name: project + "/locations/US/transferConfigs/6f1e", DestinationDatasetId: "ci_scratch", OwnerInfo: &bigquerydatatransfer.UserInfo{Email: "ci-bot@example.com"}})
+	server.Add("locations/EU/transferConfigs", &bigquerydatatransfer.TransferConfig{Name: project + "/locations/EU/transferConfigs/91ac", DestinationDatasetId: "warehouse", OwnerInfo: &bigquerydatatransfer.UserInfo{Email: "analyst@example.com"}})
+	server.Add("locations/US/reservations", &bigqueryreservation.Reservation{Name: project + "/locations/US/reservations/ci-batch", SlotCapacity: 100})
+	server.Add("locations/US/reservations/ci-batch/assignments", &bigqueryreservation.Assignment{Name: project + "/locations/US/reservations/ci-batch/assignments/1234", Assignee: project, JobType: "QUERY"})
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.Owners = []string{"ci", "ci-bot@example.com", "unowned"}
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"datasets/warehouse", "locations/EU/transferConfigs/91ac"}
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected the resources of other owners to be kept, got: %v", remaining)
+	}
+	order := deleteOrder(server)
+	assertDeletedOnce(t, order)
+	for _, before := range [][]string{
+		{"DELETE locations/US/transferConfigs/6f1e", "DELETE datasets/ci_scratch"},
+		{"DELETE locations/US/reservations/ci-batch/assignments/1234", "DELETE locations/US/reservations/ci-batch"},
+	} {
+		assertDeletedBefore(t, order, before[0], before[1])
+	}
+	if containsRequest(server, "GET", "/datasets/ci_scratch") {
+		t.Error("expected datasets to only be fetched for their creation time with --ttl-label")
+	}
+}
+
+// TestRemoveProjectBigQueryTTL - with --ttl-label datasets are fetched for their creation time, and only expired ones deleted
+func TestRemoveProjectBigQueryTTL(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Add("datasets", &bigquery.Dataset{DatasetReference: &bigquery.DatasetReference{DatasetId: "expired"}, Location: "US", Labels: map[string]string{"ttl": "1d"}, CreationTime: 1760000000000})
+	server.Add("datasets", &bigquery.Dataset{DatasetReference: &bigquery.DatasetReference{DatasetId: "fresh"}, Location: "US", Labels: map[string]string{"ttl": "1d"}, CreationTime: now().UnixNano() / int64(time.Millisecond)})
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.TTLLabels = []string{"ttl"}
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, []string{"datasets/fresh"}) {
+		t.Errorf("expected only the expired dataset to be deleted, got: %v", remaining)
+	}
+}
+
+// TestRemoveProjectBigQueryReservationsSharedWithFolders - only the assignments of the project are deleted, a reservation folders or organizations
+// are still assigned to is left in place
+func TestRemoveProjectBigQueryReservationsSharedWithFolders(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	project := "projects/" + testProject
+	server.Add("locations/US/reservations", &bigqueryreservation.Reservation{Name: project + "/locations/US/reservations/shared", SlotCapacity: 500})
+	server.Add("locations/US/reservations/shared/assignments", &bigqueryreservation.Assignment{Name: project + "/locations/US/reservations/shared/assignments/1", Assignee: project, JobType: "QUERY"})
+	server.Add("locations/US/reservations/shared/assignments", &bigqueryreservation.Assignment{Name: project + "/locations/US/reservations/shared/assignments/2", Assignee: "folders/123456", JobType: "QUERY"})
+	testConfig, clients := newTestConfig(t, server)
+
+	runReport, err := RemoveProjectReport(testConfig, clients)
+	if err != nil {
+		t.Fatalf("expected the shared reservation to be left without failing the run, got: %v", err)
+	}
+
+	expected := []string{"locations/US/reservations/shared", "locations/US/reservations/shared/assignments/2"}
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected only the assignment of the project to be deleted, got: %v", remaining)
+	}
+	reservationReport := runReport.Resource("BigQueryReservations")
+	if !reflect.DeepEqual(reservationReport.Remaining, []string{"US/shared"}) || reservationReport.Notes["US/shared"] != "assigned to folders/123456" {
+		t.Errorf("expected the reservation noted as assigned to the folder, got: %+v", reservationReport)
+	}
+}
+
+// TestRemoveProjectBigQueryAdminDisabled - the reservation and data transfer apis are off in most projects, which leaves nothing to list
+func TestRemoveProjectBigQueryAdminDisabled(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Add("datasets", &bigquery.Dataset{DatasetReference: &bigquery.DatasetReference{DatasetId: "ci_scratch"}, Location: "US", CreationTime: 1760000000000})
+	testConfig, clients := newTestConfig(t, server)
+	for _, location := range bigqueryLocations(testConfig.Regions) {
+		prefix := "/v1/projects/" + testProject + "/locations/" + location
+		server.Fail(prefix+"/reservations", http.StatusForbidden, "SERVICE_DISABLED", "BigQuery Reservation API has not been used in project "+testProject+" before or it is disabled.")
+		server.Fail(prefix+"/transferConfigs", http.StatusForbidden, "SERVICE_DISABLED", "BigQuery Data Transfer API has not been used in project "+testProject+" before or it is disabled.")
+	}
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+	if remaining := server.Paths(); len(remaining) != 0 {
+		t.Errorf("expected the dataset to be deleted, remaining: %v", remaining)
+	}
+}
@@ -0,0 +1,112 @@
+package gcp
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/bigquerydatatransfer/v1"
+)
+
+// BigQueryTransferConfigs - data transfer configs and scheduled queries of every location, keyed by location/config id
+// Configs have neither labels nor a creation time, their owner is the user they run as
+type BigQueryTransferConfigs struct {
+	serviceClient *bigquerydatatransfer.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &BigQueryTransferConfigs{}
+	})
+}
+
+// Name - Name of the resourceLister for BigQueryTransferConfigs
+func (c *BigQueryTransferConfigs) Name() string {
+	return "BigQueryTransferConfigs"
+}
+
+// ToSlice - Name of the resourceLister for BigQueryTransferConfigs
+func (c *BigQueryTransferConfigs) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *BigQueryTransferConfigs) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.BigQueryDataTransfer()
+	return err
+}
+
+// List - Returns a list of all BigQueryTransferConfigs
+func (c *BigQueryTransferConfigs) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	for _, location := range bigqueryLocations(c.base.config.Regions) {
+		configListCall := c.serviceClient.Projects.Locations.TransferConfigs.List("projects/" + c.base.config.Project + "/locations/" + location)
+		err := configListCall.Pages(ctx, func(configList *bigquerydatatransfer.ListTransferConfigsResponse) error {
+			for _, transferConfig := range configList.TransferConfigs {
+				configKey := regionalKey(location, keyName(transferConfig.Name))
+				createdBy := ""
+				if transferConfig.OwnerInfo != nil {
+					createdBy = transferConfig.OwnerInfo.Email
+				}
+				// Transfer configs have neither labels nor a creation time, only the owner is there for the filters
+				if c.base.skip(c.Name(), listedItem{name: configKey, createdBy: createdBy}) {
+					continue
+				}
+				c.resourceMap.Store(configKey, transferConfig.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			if c.base.apiDisabled(err) {
+				return c.ToSlice(), nil
+			}
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *BigQueryTransferConfigs) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *BigQueryTransferConfigs) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		configKey := key.(string)
+		configName := value.(string)
+		location := strings.Split(configName, "/")[3]
+
+		// Parallel transfer config deletion
+		errs.Go(configKey, location, func(ctx context.Context) error {
+			if _, err := c.serviceClient.Projects.Locations.TransferConfigs.Delete(configName).Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v location: %v]", keyName(configKey), c.Name(), c.base.config.Project, location)
+			c.resourceMap.Delete(configKey)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -0,0 +1,236 @@
+// Package cassette - records real API traffic into cassette files and replays it, for offline regression tests
+//
+// Only the request method, path, query and body are kept, along with the response status, content type and body.
+// Request headers are never recorded, and tokens are scrubbed from urls and bodies before saving.
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"sync"
+)
+
+// Cassette - recorded interactions, in the order they happened
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction - a single request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request -
+type Request struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response -
+type Response struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        string `json:"body,omitempty"`
+}
+
+// secretPatterns - anything that looks like a credential is replaced before a cassette is saved
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`ya29\.[A-Za-z0-9_\-.]+`),
+	regexp.MustCompile(`("(?:access_token|id_token|refresh_token|private_key|private_key_id|client_secret)"\s*:\s*")[^"]*(")`),
+	regexp.MustCompile(`([?&](?:access_token|key)=)[^&]+`),
+}
+
+// Scrub - replaces tokens and keys in the value
+func Scrub(value string) string {
+	for _, pattern := range secretPatterns {
+		if pattern.NumSubexp() == 0 {
+			value = pattern.ReplaceAllString(value, "REDACTED")
+			continue
+		}
+		value = pattern.ReplaceAllString(value, "${1}REDACTED${2}")
+	}
+	return value
+}
+
+// Load - reads a cassette file
+func Load(path string) (*Cassette, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err := json.Unmarshal(content, cassette); err != nil {
+		return nil, fmt.Errorf("invalid cassette %v: %v", path, err)
+	}
+	return cassette, nil
+}
+
+// Save - writes the cassette file
+func (c *Cassette) Save(path string) error {
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(content, '\n'), 0644)
+}
+
+// Recorder - round tripper which records every interaction passing through to the base transport
+type Recorder struct {
+	base     http.RoundTripper
+	mutex    sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder - records interactions sent through base
+func NewRecorder(base http.RoundTripper) *Recorder {
+	return &Recorder{base: base}
+}
+
+// RoundTrip -
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	responseBody, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    Scrub(req.URL.String()),
+			Body:   Scrub(requestBody),
+		},
+		Response: Response{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+			Body:        Scrub(responseBody),
+		},
+	})
+	return resp, nil
+}
+
+// Save - writes everything recorded so far to the cassette file
+func (r *Recorder) Save(path string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.cassette.Save(path)
+}
+
+// Replayer - round tripper which answers requests from a cassette, without any network access
+//
+// Requests are matched on method, path, query and body, ignoring the host. Repeated requests get the recorded
+// responses in order, and once those run out the last one is repeated, as polling loops rarely poll the same
+// number of times twice.
+type Replayer struct {
+	mutex        sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer - replays the interactions of the cassette
+func NewReplayer(cassette *Cassette) *Replayer {
+	return &Replayer{
+		interactions: cassette.Interactions,
+		used:         make([]bool, len(cassette.Interactions)),
+	}
+}
+
+// RoundTrip -
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	key := requestKey(req.Method, req.URL.RequestURI(), requestBody)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	last := -1
+	for i, interaction := range r.interactions {
+		if interactionKey(interaction) != key {
+			continue
+		}
+		last = i
+		if !r.used[i] {
+			r.used[i] = true
+			return response(req, interaction.Response), nil
+		}
+	}
+	if last == -1 {
+		return nil, fmt.Errorf("cassette: no recorded interaction for %v %v", req.Method, req.URL.RequestURI())
+	}
+	return response(req, r.interactions[last].Response), nil
+}
+
+// Unused - recorded interactions which were never replayed, useful to check a regression still follows the recording
+func (r *Replayer) Unused() []Request {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	unused := []Request{}
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction.Request)
+		}
+	}
+	return unused
+}
+
+func interactionKey(interaction Interaction) string {
+	recordedURL, err := http.NewRequest(interaction.Request.Method, interaction.Request.URL, nil)
+	if err != nil {
+		return ""
+	}
+	return requestKey(interaction.Request.Method, recordedURL.URL.RequestURI(), interaction.Request.Body)
+}
+
+func requestKey(method, requestURI, body string) string {
+	return method + " " + Scrub(requestURI) + " " + Scrub(body)
+}
+
+func response(req *http.Request, recorded Response) *http.Response {
+	header := http.Header{}
+	if recorded.ContentType != "" {
+		header.Set("Content-Type", recorded.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
+
+// readBody - reads the body, leaving an identical unread copy in its place
+func readBody(body *io.ReadCloser) (string, error) {
+	if *body == nil {
+		return "", nil
+	}
+	content, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return "", err
+	}
+	*body = ioutil.NopCloser(bytes.NewReader(content))
+	return string(content), nil
+}
@@ -0,0 +1,76 @@
+package cassette
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestScrub(t *testing.T) {
+	tests := map[string]string{
+		"Bearer ya29.a0AfH6SMB-secret_token":               "Bearer REDACTED",
+		`{"access_token": "secret", "expires_in": 10}`:     `{"access_token": "REDACTED", "expires_in": 10}`,
+		`{"private_key":"-----BEGIN-----"}`:                `{"private_key":"REDACTED"}`,
+		"/compute/v1/projects/p/zones?key=secret&alt=json": "/compute/v1/projects/p/zones?key=REDACTED&alt=json",
+		"/compute/v1/projects/p/zones?alt=json":            "/compute/v1/projects/p/zones?alt=json",
+	}
+	for value, expected := range tests {
+		if scrubbed := Scrub(value); scrubbed != expected {
+			t.Errorf("Scrub(%q) = %q, expected %q", value, scrubbed, expected)
+		}
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"status":"poll-%d"}`, atomic.AddInt32(&polls, 1))
+	}))
+	defer server.Close()
+
+	recorder := NewRecorder(http.DefaultTransport)
+	for i := 0; i < 2; i++ {
+		get(t, &http.Client{Transport: recorder}, server.URL+"/operations/op?alt=json")
+	}
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	if err := recorder.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	recorded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer := NewReplayer(recorded)
+	client := &http.Client{Transport: replayer}
+	for _, expected := range []string{"poll-1", "poll-2", "poll-2"} {
+		if body := get(t, client, "https://example.com/operations/op?alt=json"); !strings.Contains(body, expected) {
+			t.Errorf("expected %v to be replayed, got %v", expected, body)
+		}
+	}
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("expected every interaction to be replayed, never replayed: %v", unused)
+	}
+	if _, err := client.Get("https://example.com/operations/other"); err == nil {
+		t.Error("expected an error for a request which was never recorded")
+	}
+}
+
+func get(t *testing.T, client *http.Client, url string) string {
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(body)
+}
@@ -0,0 +1,354 @@
+package gcp
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/bigquerydatatransfer/v1"
+	"google.golang.org/api/bigqueryreservation/v1"
+	"google.golang.org/api/cloudfunctions/v2"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/container/v1"
+	"google.golang.org/api/dns/v1"
+	"google.golang.org/api/iam/v1"
+	"google.golang.org/api/impersonate"
+	"google.golang.org/api/option"
+	"google.golang.org/api/pubsub/v1"
+	"google.golang.org/api/run/v2"
+	"google.golang.org/api/sqladmin/v1beta4"
+	"google.golang.org/api/storage/v1"
+	htransport "google.golang.org/api/transport/http"
+	"google.golang.org/api/vpcaccess/v1"
+)
+
+// cloudPlatformScope - every API client is authorised with the cloud-platform scope
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+// apiBasePaths - base path of each api on the --endpoint host, matching the paths of the google endpoints
+var apiBasePaths = map[string]string{
+	"bigquery":             "bigquery/v2/",
+	"bigquerydatatransfer": "",
+	"bigqueryreservation":  "",
+	"cloudfunctions":       "",
+	"compute":              "compute/v1/",
+	"container":            "",
+	"dns":                  "",
+	"iam":                  "",
+	"pubsub":               "",
+	"run":                  "",
+	"sqladmin":             "",
+	"storage":              "storage/v1/",
+	"vpcaccess":            "",
+}
+
+// Clients - builds every API service client from a single set of credentials, once the config has been parsed
+type Clients struct {
+	// Transport - base transport under every api client, set it before building any client to e.g. record or replay a cassette
+	Transport http.RoundTripper
+
+	config   config.Config
+	mutex    sync.Mutex
+	throttle *throttle
+
+	authOptions          []option.ClientOption
+	bigquery             *bigquery.Service
+	bigquerydatatransfer *bigquerydatatransfer.Service
+	bigqueryreservation  *bigqueryreservation.Service
+	cloudfunctions       *cloudfunctions.Service
+	compute              *compute.Service
+	container            *container.Service
+	dns                  *dns.Service
+	iam                  *iam.Service
+	pubsub               *pubsub.Service
+	run                  *run.Service
+	sqladmin             *sqladmin.Service
+	storage              *storage.Service
+	vpcaccess            *vpcaccess.Service
+}
+
+// NewClients - client factory for the credentials, impersonation and quota project in the config
+func NewClients(config config.Config) *Clients {
+	return &Clients{config: config, throttle: newThrottle(config)}
+}
+
+// BigQuery - shared bigquery api client
+func (c *Clients) BigQuery() (*bigquery.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.bigquery != nil {
+		return c.bigquery, nil
+	}
+	serviceOptions, err := c.serviceOptions("bigquery")
+	if err != nil {
+		return nil, err
+	}
+	c.bigquery, err = bigquery.NewService(c.config.Context, serviceOptions...)
+	return c.bigquery, err
+}
+
+// BigQueryDataTransfer - shared bigquery data transfer api client
+func (c *Clients) BigQueryDataTransfer() (*bigquerydatatransfer.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.bigquerydatatransfer != nil {
+		return c.bigquerydatatransfer, nil
+	}
+	serviceOptions, err := c.serviceOptions("bigquerydatatransfer")
+	if err != nil {
+		return nil, err
+	}
+	c.bigquerydatatransfer, err = bigquerydatatransfer.NewService(c.config.Context, serviceOptions...)
+	return c.bigquerydatatransfer, err
+}
+
+// BigQueryReservation - shared bigquery reservation api client
+func (c *Clients) BigQueryReservation() (*bigqueryreservation.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.bigqueryreservation != nil {
+		return c.bigqueryreservation, nil
+	}
+	serviceOptions, err := c.serviceOptions("bigqueryreservation")
+	if err != nil {
+		return nil, err
+	}
+	c.bigqueryreservation, err = bigqueryreservation.NewService(c.config.Context, serviceOptions...)
+	return c.bigqueryreservation, err
+}
+
+// Compute - shared compute api client
+func (c *Clients) Compute() (*compute.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.compute != nil {
+		return c.compute, nil
+	}
+	serviceOptions, err := c.serviceOptions("compute")
+	if err != nil {
+		return nil, err
+	}
+	c.compute, err = compute.NewService(c.config.Context, serviceOptions...)
+	return c.compute, err
+}
+
+// Container - shared container api client
+func (c *Clients) Container() (*container.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.container != nil {
+		return c.container, nil
+	}
+	serviceOptions, err := c.serviceOptions("container")
+	if err != nil {
+		return nil, err
+	}
+	c.container, err = container.NewService(c.config.Context, serviceOptions...)
+	return c.container, err
+}
+
+// DNS - shared cloud dns api client
+func (c *Clients) DNS() (*dns.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.dns != nil {
+		return c.dns, nil
+	}
+	serviceOptions, err := c.serviceOptions("dns")
+	if err != nil {
+		return nil, err
+	}
+	c.dns, err = dns.NewService(c.config.Context, serviceOptions...)
+	return c.dns, err
+}
+
+// IAM - shared iam api client
+func (c *Clients) IAM() (*iam.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.iam != nil {
+		return c.iam, nil
+	}
+	serviceOptions, err := c.serviceOptions("iam")
+	if err != nil {
+		return nil, err
+	}
+	c.iam, err = iam.NewService(c.config.Context, serviceOptions...)
+	return c.iam, err
+}
+
+// Storage - shared cloud storage api client
+func (c *Clients) Storage() (*storage.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.storage != nil {
+		return c.storage, nil
+	}
+	serviceOptions, err := c.serviceOptions("storage")
+	if err != nil {
+		return nil, err
+	}
+	c.storage, err = storage.NewService(c.config.Context, serviceOptions...)
+	return c.storage, err
+}
+
+// PubSub - shared pub/sub api client
+func (c *Clients) PubSub() (*pubsub.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.pubsub != nil {
+		return c.pubsub, nil
+	}
+	serviceOptions, err := c.serviceOptions("pubsub")
+	if err != nil {
+		return nil, err
+	}
+	c.pubsub, err = pubsub.NewService(c.config.Context, serviceOptions...)
+	return c.pubsub, err
+}
+
+// Run - shared cloud run admin api client
+func (c *Clients) Run() (*run.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.run != nil {
+		return c.run, nil
+	}
+	serviceOptions, err := c.serviceOptions("run")
+	if err != nil {
+		return nil, err
+	}
+	c.run, err = run.NewService(c.config.Context, serviceOptions...)
+	return c.run, err
+}
+
+// CloudFunctions - shared cloud functions api client, the v2 api covers both 1st and 2nd gen functions
+func (c *Clients) CloudFunctions() (*cloudfunctions.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.cloudfunctions != nil {
+		return c.cloudfunctions, nil
+	}
+	serviceOptions, err := c.serviceOptions("cloudfunctions")
+	if err != nil {
+		return nil, err
+	}
+	c.cloudfunctions, err = cloudfunctions.NewService(c.config.Context, serviceOptions...)
+	return c.cloudfunctions, err
+}
+
+// SQLAdmin - shared cloud sql admin api client
+func (c *Clients) SQLAdmin() (*sqladmin.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.sqladmin != nil {
+		return c.sqladmin, nil
+	}
+	serviceOptions, err := c.serviceOptions("sqladmin")
+	if err != nil {
+		return nil, err
+	}
+	c.sqladmin, err = sqladmin.NewService(c.config.Context, serviceOptions...)
+	return c.sqladmin, err
+}
+
+// VPCAccess - shared serverless vpc access api client
+func (c *Clients) VPCAccess() (*vpcaccess.Service, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.vpcaccess != nil {
+		return c.vpcaccess, nil
+	}
+	serviceOptions, err := c.serviceOptions("vpcaccess")
+	if err != nil {
+		return nil, err
+	}
+	c.vpcaccess, err = vpcaccess.NewService(c.config.Context, serviceOptions...)
+	return c.vpcaccess, err
+}
+
+// serviceOptions - options for building an api service, pointing at the --endpoint override if set
+func (c *Clients) serviceOptions(api string) ([]option.ClientOption, error) {
+	httpClient, err := c.httpClient(api)
+	if err != nil {
+		return nil, err
+	}
+	serviceOptions := []option.ClientOption{option.WithHTTPClient(httpClient)}
+	if c.config.Endpoint != "" {
+		endpoint := strings.TrimSuffix(c.config.Endpoint, "/") + "/" + apiBasePaths[api]
+		serviceOptions = append(serviceOptions, option.WithEndpoint(endpoint))
+	}
+	return serviceOptions, nil
+}
+
+// httpClient - authenticated http client for the api, throttled by the api limiter of the run
+func (c *Clients) httpClient(api string) (*http.Client, error) {
+	authOptions, err := c.credentials()
+	if err != nil {
+		return nil, err
+	}
+	baseTransport := c.Transport
+	if baseTransport == nil {
+		baseTransport = http.DefaultTransport
+	}
+	base := &rateLimitedTransport{
+		limiter: c.throttle.apiLimiter(api),
+		base: &metricsTransport{
+			api:  api,
+			base: baseTransport,
+		},
+	}
+	transport, err := htransport.NewTransport(c.config.Context, base, authOptions...)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// credentials - client options shared by every api, so an impersonated token is only minted once
+func (c *Clients) credentials() ([]option.ClientOption, error) {
+	if c.authOptions != nil {
+		return c.authOptions, nil
+	}
+
+	if c.config.WithoutAuthentication {
+		c.authOptions = []option.ClientOption{option.WithoutAuthentication()}
+		return c.authOptions, nil
+	}
+
+	authOptions := []option.ClientOption{option.WithScopes(cloudPlatformScope)}
+	if c.config.CredentialsFile != "" {
+		authOptions = append(authOptions, option.WithCredentialsFile(c.config.CredentialsFile))
+	}
+	if c.config.ImpersonateServiceAccount != "" {
+		tokenSource, err := impersonate.CredentialsTokenSource(c.config.Context, impersonate.CredentialsConfig{
+			TargetPrincipal: c.config.ImpersonateServiceAccount,
+			Scopes:          []string{cloudPlatformScope},
+		}, authOptions...)
+		if err != nil {
+			return nil, err
+		}
+		authOptions = []option.ClientOption{option.WithTokenSource(tokenSource)}
+	}
+	if c.config.QuotaProject != "" {
+		authOptions = append(authOptions, option.WithQuotaProject(c.config.QuotaProject))
+	}
+
+	c.authOptions = authOptions
+	return c.authOptions, nil
+}
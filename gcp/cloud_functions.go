@@ -0,0 +1,112 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/cloudfunctions/v2"
+)
+
+// CloudFunctions - 1st and 2nd gen Cloud Functions of every location, keyed by location/name
+// 2nd gen functions take their Cloud Run service with them. Functions have no creation time, so only a ttl label holding a date expires them
+type CloudFunctions struct {
+	serviceClient *cloudfunctions.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &CloudFunctions{}
+	})
+}
+
+// Name - Name of the resourceLister for CloudFunctions
+func (c *CloudFunctions) Name() string {
+	return "CloudFunctions"
+}
+
+// ToSlice - Name of the resourceLister for CloudFunctions
+func (c *CloudFunctions) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *CloudFunctions) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.CloudFunctions()
+	return err
+}
+
+// List - Returns a list of all CloudFunctions
+func (c *CloudFunctions) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	functionListCall := c.serviceClient.Projects.Locations.Functions.List(fmt.Sprintf("projects/%v/locations/-", c.base.config.Project))
+	err := functionListCall.Pages(ctx, func(functionList *cloudfunctions.ListFunctionsResponse) error {
+		for _, function := range functionList.Functions {
+			functionKey := regionalKey(strings.Split(function.Name, "/")[3], keyName(function.Name))
+			if c.base.skip(c.Name(), listedItem{name: functionKey, labels: function.Labels}) {
+				continue
+			}
+			c.resourceMap.Store(functionKey, function.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *CloudFunctions) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *CloudFunctions) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		functionKey := key.(string)
+		functionName := value.(string)
+		location := strings.Split(functionName, "/")[3]
+
+		// Parallel function deletion
+		errs.Go(functionKey, location, func(ctx context.Context) error {
+			operation, err := c.serviceClient.Projects.Locations.Functions.Delete(functionName).Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v location: %v]", keyName(functionKey), c.Name(), c.base.config.Project, location)
+			err = c.base.waitForOperation(ctx, c.Name(), description, longRunningOperationStatus(operation.Name, func() (interface{}, error) {
+				return c.serviceClient.Projects.Locations.Operations.Get(operation.Name).Context(ctx).Do()
+			}))
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(functionKey)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -0,0 +1,113 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/run/v2"
+)
+
+// CloudRunJobs - Cloud Run jobs of every region, keyed by region/name, deleting a job deletes its executions
+type CloudRunJobs struct {
+	serviceClient *run.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &CloudRunJobs{}
+	})
+}
+
+// Name - Name of the resourceLister for CloudRunJobs
+func (c *CloudRunJobs) Name() string {
+	return "CloudRunJobs"
+}
+
+// ToSlice - Name of the resourceLister for CloudRunJobs
+func (c *CloudRunJobs) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *CloudRunJobs) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Run()
+	return err
+}
+
+// List - Returns a list of all CloudRunJobs
+func (c *CloudRunJobs) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	for _, region := range c.base.config.Regions {
+		jobListCall := c.serviceClient.Projects.Locations.Jobs.List(fmt.Sprintf("projects/%v/locations/%v", c.base.config.Project, region))
+		err := jobListCall.Pages(ctx, func(jobList *run.GoogleCloudRunV2ListJobsResponse) error {
+			for _, job := range jobList.Jobs {
+				jobKey := regionalKey(region, keyName(job.Name))
+				if c.base.skip(c.Name(), listedItem{name: jobKey, labels: job.Labels, created: job.CreateTime, createdBy: job.Creator}) {
+					continue
+				}
+				c.resourceMap.Store(jobKey, job.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			if c.base.apiDisabled(err) {
+				return c.ToSlice(), nil
+			}
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *CloudRunJobs) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *CloudRunJobs) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		jobKey := key.(string)
+		jobName := value.(string)
+		region := strings.Split(jobName, "/")[3]
+
+		// Parallel job deletion
+		errs.Go(jobKey, region, func(ctx context.Context) error {
+			operation, err := c.serviceClient.Projects.Locations.Jobs.Delete(jobName).Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v region: %v]", keyName(jobKey), c.Name(), c.base.config.Project, region)
+			err = c.base.waitForOperation(ctx, c.Name(), description, longRunningOperationStatus(operation.Name, func() (interface{}, error) {
+				return c.serviceClient.Projects.Locations.Operations.Get(operation.Name).Context(ctx).Do()
+			}))
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(jobKey)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -0,0 +1,117 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/run/v2"
+)
+
+// CloudRunServices - Cloud Run services of every region, keyed by region/name
+// The services of 2nd gen Cloud Functions are left to CloudFunctions, which deletes them along with the function
+type CloudRunServices struct {
+	serviceClient *run.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &CloudRunServices{}
+	})
+}
+
+// Name - Name of the resourceLister for CloudRunServices
+func (c *CloudRunServices) Name() string {
+	return "CloudRunServices"
+}
+
+// ToSlice - Name of the resourceLister for CloudRunServices
+func (c *CloudRunServices) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *CloudRunServices) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Run()
+	return err
+}
+
+// List - Returns a list of all CloudRunServices
+func (c *CloudRunServices) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	for _, region := range c.base.config.Regions {
+		serviceListCall := c.serviceClient.Projects.Locations.Services.List(fmt.Sprintf("projects/%v/locations/%v", c.base.config.Project, region))
+		err := serviceListCall.Pages(ctx, func(serviceList *run.GoogleCloudRunV2ListServicesResponse) error {
+			for _, service := range serviceList.Services {
+				if service.Labels["goog-managed-by"] == "cloudfunctions" {
+					continue
+				}
+				serviceKey := regionalKey(region, keyName(service.Name))
+				if c.base.skip(c.Name(), listedItem{name: serviceKey, labels: service.Labels, created: service.CreateTime, createdBy: service.Creator}) {
+					continue
+				}
+				c.resourceMap.Store(serviceKey, service.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			if c.base.apiDisabled(err) {
+				return c.ToSlice(), nil
+			}
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *CloudRunServices) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *CloudRunServices) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		serviceKey := key.(string)
+		serviceName := value.(string)
+		region := strings.Split(serviceName, "/")[3]
+
+		// Parallel service deletion
+		errs.Go(serviceKey, region, func(ctx context.Context) error {
+			operation, err := c.serviceClient.Projects.Locations.Services.Delete(serviceName).Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v region: %v]", keyName(serviceKey), c.Name(), c.base.config.Project, region)
+			err = c.base.waitForOperation(ctx, c.Name(), description, longRunningOperationStatus(operation.Name, func() (interface{}, error) {
+				return c.serviceClient.Projects.Locations.Operations.Get(operation.Name).Context(ctx).Do()
+			}))
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(serviceKey)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
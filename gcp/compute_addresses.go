@@ -0,0 +1,110 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeAddresses - regional static external and internal addresses, removed once the forwarding rules, vpn gateways, cloud nat routers and instances using them are gone
+type ComputeAddresses struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeAddresses{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeAddresses
+func (c *ComputeAddresses) Name() string {
+	return "ComputeAddresses"
+}
+
+// ToSlice - Name of the resourceLister for ComputeAddresses
+func (c *ComputeAddresses) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeAddresses) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeAddresses
+func (c *ComputeAddresses) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	for _, region := range c.base.config.Regions {
+		addressListCall := c.serviceClient.Addresses.List(c.base.config.Project, region)
+		err := addressListCall.Pages(ctx, func(addressList *compute.AddressList) error {
+			for _, address := range addressList.Items {
+				if c.base.skip(c.Name(), listedItem{name: address.Name, labels: address.Labels, created: address.CreationTimestamp, monthlyCost: addressCost(c.base.prices(), address, region)}) {
+					continue
+				}
+				c.resourceMap.Store(address.Name, region)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeAddresses) Dependencies() []string {
+	a := ComputeForwardingRules{}
+	b := ComputeVPNGateways{}
+	d := ComputeRouters{}
+	e := ComputeInstances{}
+	return []string{a.Name(), b.Name(), d.Name(), e.Name()}
+}
+
+// Remove -
+func (c *ComputeAddresses) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		addressID := key.(string)
+		region := value.(string)
+
+		// Parallel address deletion
+		errs.Go(addressID, region, func(ctx context.Context) error {
+			deleteCall := c.serviceClient.Addresses.Delete(c.base.config.Project, region, addressID)
+			operation, err := deleteCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v region: %v]", addressID, c.Name(), c.base.config.Project, region)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), region, operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(addressID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
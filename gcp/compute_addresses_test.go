@@ -0,0 +1,74 @@
+package gcp
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// TestRemoveProjectAddresses - addresses are released once nothing uses them, and private service access ranges before their peering and network
+func TestRemoveProjectAddresses(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	region := "regions/" + testRegion
+	network := server.SelfLink("global/networks/vpc")
+
+	server.Add("global/networks", &compute.Network{
+		Name:     "vpc",
+		Peerings: []*compute.NetworkPeering{{Name: "servicenetworking-googleapis-com", Network: "https://www.googleapis.com/compute/v1/projects/tenant/global/networks/servicenetworking"}},
+	})
+	server.Add("global/addresses", &compute.Address{Name: "google-managed-services-vpc", Purpose: "VPC_PEERING", AddressType: "INTERNAL", PrefixLength: 16, Network: network})
+	server.Add("global/addresses", &compute.Address{Name: "web", Status: "IN_USE"})
+	server.Add("global/forwardingRules", &compute.ForwardingRule{Name: "web", IPAddress: server.SelfLink("global/addresses/web")})
+	server.Add(region+"/addresses", &compute.Address{Name: "nat", Status: "IN_USE"})
+	server.Add(region+"/routers", &compute.Router{Name: "router", Network: network, Nats: []*compute.RouterNat{{Name: "nat", NatIps: []string{server.SelfLink(region + "/addresses/nat")}}}})
+	server.Add(region+"/addresses", &compute.Address{Name: "lb", Status: "IN_USE"})
+	server.Add(region+"/forwardingRules", &compute.ForwardingRule{Name: "lb", IPAddress: server.SelfLink(region + "/addresses/lb")})
+	server.Add(region+"/addresses", &compute.Address{Name: "spare", Status: "RESERVED"})
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := server.Paths(); len(remaining) != 0 {
+		t.Errorf("expected every address to be released, remaining: %v", remaining)
+	}
+	order := deleteOrder(server)
+	assertDeletedOnce(t, order)
+	for _, before := range [][]string{
+		{"DELETE global/forwardingRules/web", "DELETE global/addresses/web"},
+		{"DELETE " + region + "/forwardingRules/lb", "DELETE " + region + "/addresses/lb"},
+		{"DELETE " + region + "/routers/router", "DELETE " + region + "/addresses/nat"},
+		{"DELETE global/addresses/google-managed-services-vpc", "POST global/networks/vpc/removePeering"},
+		{"DELETE global/addresses/google-managed-services-vpc", "DELETE global/networks/vpc"},
+	} {
+		assertDeletedBefore(t, order, before[0], before[1])
+	}
+}
+
+// TestRemoveProjectAddressesLabels - the ttl and owner filters apply to addresses through their labels
+func TestRemoveProjectAddressesLabels(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	region := "regions/" + testRegion
+	created := "2026-10-01T08:00:00.000-07:00"
+	server.Add(region+"/addresses", &compute.Address{Name: "expired", Status: "RESERVED", CreationTimestamp: created, Labels: map[string]string{"ttl": "1d", "owner": "ci"}})
+	server.Add(region+"/addresses", &compute.Address{Name: "fresh", Status: "RESERVED", CreationTimestamp: created, Labels: map[string]string{"ttl": "90d", "owner": "ci"}})
+	server.Add(region+"/addresses", &compute.Address{Name: "other-owner", Status: "RESERVED", CreationTimestamp: created, Labels: map[string]string{"ttl": "1d", "owner": "data"}})
+	server.Add("global/addresses", &compute.Address{Name: "expired", Status: "RESERVED", CreationTimestamp: created, Labels: map[string]string{"ttl": "1d", "owner": "ci"}})
+	server.Add("global/addresses", &compute.Address{Name: "unlabelled", Status: "RESERVED", CreationTimestamp: created})
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.TTLLabels = []string{"ttl"}
+	testConfig.Owners = []string{"ci"}
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"global/addresses/unlabelled", region + "/addresses/fresh", region + "/addresses/other-owner"}
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected only the expired addresses of ci to be released, got: %v", remaining)
+	}
+}
@@ -0,0 +1,104 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeBackendBuckets - backend buckets, removed once the url maps using them are gone
+type ComputeBackendBuckets struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeBackendBuckets{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeBackendBuckets
+func (c *ComputeBackendBuckets) Name() string {
+	return "ComputeBackendBuckets"
+}
+
+// ToSlice - Name of the resourceLister for ComputeBackendBuckets
+func (c *ComputeBackendBuckets) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeBackendBuckets) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeBackendBuckets
+func (c *ComputeBackendBuckets) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	backendBucketListCall := c.serviceClient.BackendBuckets.List(c.base.config.Project)
+	err := backendBucketListCall.Pages(ctx, func(backendBucketList *compute.BackendBucketList) error {
+		for _, backendBucket := range backendBucketList.Items {
+			if c.base.skip(c.Name(), listedItem{name: backendBucket.Name, created: backendBucket.CreationTimestamp}) {
+				continue
+			}
+			c.resourceMap.Store(backendBucket.Name, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeBackendBuckets) Dependencies() []string {
+	a := ComputeURLMaps{}
+	return []string{a.Name()}
+}
+
+// Remove -
+func (c *ComputeBackendBuckets) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		backendBucketID := key.(string)
+
+		// Parallel backend bucket deletion
+		errs.Go(backendBucketID, "global", func(ctx context.Context) error {
+			deleteCall := c.serviceClient.BackendBuckets.Delete(c.base.config.Project, backendBucketID)
+			operation, err := deleteCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v]", backendBucketID, c.Name(), c.base.config.Project)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), "", operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(backendBucketID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -0,0 +1,133 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeBackendServices - global and regional backend services, removed once the url maps, proxies and internal forwarding rules using them are gone
+type ComputeBackendServices struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeBackendServices{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeBackendServices
+func (c *ComputeBackendServices) Name() string {
+	return "ComputeBackendServices"
+}
+
+// ToSlice - Name of the resourceLister for ComputeBackendServices
+func (c *ComputeBackendServices) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeBackendServices) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeBackendServices, global ones by name and regional ones by region/name
+func (c *ComputeBackendServices) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	store := func(backendService *compute.BackendService, region string) {
+		if c.base.skip(c.Name(), listedItem{name: regionalKey(region, backendService.Name), created: backendService.CreationTimestamp}) {
+			return
+		}
+		c.resourceMap.Store(regionalKey(region, backendService.Name), region)
+	}
+
+	backendServiceListCall := c.serviceClient.BackendServices.List(c.base.config.Project)
+	err := backendServiceListCall.Pages(ctx, func(backendServiceList *compute.BackendServiceList) error {
+		for _, backendService := range backendServiceList.Items {
+			store(backendService, "")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, region := range c.base.config.Regions {
+		regionListCall := c.serviceClient.RegionBackendServices.List(c.base.config.Project, region)
+		err := regionListCall.Pages(ctx, func(backendServiceList *compute.BackendServiceList) error {
+			for _, backendService := range backendServiceList.Items {
+				store(backendService, region)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeBackendServices) Dependencies() []string {
+	a := ComputeURLMaps{}
+	b := ComputeTargetTCPProxies{}
+	d := ComputeTargetSSLProxies{}
+	e := ComputeForwardingRules{}
+	return []string{a.Name(), b.Name(), d.Name(), e.Name()}
+}
+
+// Remove -
+func (c *ComputeBackendServices) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		backendServiceID := key.(string)
+		region := value.(string)
+		location := region
+		if region == "" {
+			location = "global"
+		}
+
+		// Parallel backend service deletion
+		errs.Go(backendServiceID, location, func(ctx context.Context) error {
+			var operation *compute.Operation
+			var err error
+			if region == "" {
+				operation, err = c.serviceClient.BackendServices.Delete(c.base.config.Project, keyName(backendServiceID)).Context(ctx).Do()
+			} else {
+				operation, err = c.serviceClient.RegionBackendServices.Delete(c.base.config.Project, region, keyName(backendServiceID)).Context(ctx).Do()
+			}
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v location: %v]", keyName(backendServiceID), c.Name(), c.base.config.Project, location)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), region, operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(backendServiceID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -1,14 +1,13 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
+	"github.com/arehmandev/gcp-nuke/pricing"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -21,14 +20,9 @@ type ComputeDisks struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeDisks{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeDisks{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeDisks
@@ -43,38 +37,48 @@ func (c *ComputeDisks) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeDisks) Setup(config config.Config) {
+func (c *ComputeDisks) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeDisks
-func (c *ComputeDisks) List(refreshCache bool) []string {
+func (c *ComputeDisks) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	for _, zone := range c.base.config.Zones {
 		instanceListCall := c.serviceClient.Disks.List(c.base.config.Project, zone)
-		instanceList, err := instanceListCall.Do()
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		for _, instance := range instanceList.Items {
-			// Don't delete any attached to instances - these are removed during instance deletion
-			if len(instance.Users) > 0 {
-				continue
-			}
-			instanceResource := DefaultResourceProperties{
-				zone: zone,
+		err := instanceListCall.Pages(ctx, func(instanceList *compute.DiskList) error {
+			for _, instance := range instanceList.Items {
+				// Don't delete any attached to instances - these are removed during instance deletion
+				if len(instance.Users) > 0 {
+					continue
+				}
+				if c.base.skip(c.Name(), listedItem{
+					name:        instance.Name,
+					labels:      instance.Labels,
+					created:     instance.CreationTimestamp,
+					monthlyCost: c.base.prices().Disk(instance.Type, instance.SizeGb, pricing.Region(zone)),
+				}) {
+					continue
+				}
+				instanceResource := DefaultResourceProperties{
+					zone: zone,
+				}
+				c.resourceMap.Store(instance.Name, instanceResource)
 			}
-			c.resourceMap.Store(instance.Name, instanceResource)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
@@ -83,43 +87,38 @@ func (c *ComputeDisks) Dependencies() []string {
 }
 
 // Remove -
-func (c *ComputeDisks) Remove() error {
+func (c *ComputeDisks) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		instanceID := key.(string)
 		zone := value.(DefaultResourceProperties).zone
 
 		// Parallel instance deletion
-		errs.Go(func() error {
+		errs.Go(instanceID, zone, func(ctx context.Context) error {
+			if err := c.base.snapshotDisk(ctx, c.serviceClient, c.Name(), "zones/"+zone, instanceID); err != nil {
+				return err
+			}
 			deleteCall := c.serviceClient.Disks.Delete(c.base.config.Project, zone, instanceID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, seconds)
-
+			description := fmt.Sprintf("%v [type: %v project: %v zone: %v]", instanceID, c.Name(), c.base.config.Project, zone)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.ZoneOperations.Get(c.base.config.Project, zone, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(instanceID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, seconds)
 			return nil
 		})
 		return true
@@ -1,14 +1,12 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -21,14 +19,9 @@ type ComputeFirewalls struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeFirewalls{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeFirewalls{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeFirewalls
@@ -43,29 +36,34 @@ func (c *ComputeFirewalls) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeFirewalls) Setup(config config.Config) {
+func (c *ComputeFirewalls) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeFirewalls
-func (c *ComputeFirewalls) List(refreshCache bool) []string {
+func (c *ComputeFirewalls) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	firewallListCall := c.serviceClient.Firewalls.List(c.base.config.Project)
-	firewallList, err := firewallListCall.Do()
+	err := firewallListCall.Pages(ctx, func(firewallList *compute.FirewallList) error {
+		for _, firewall := range firewallList.Items {
+			if c.base.skip(c.Name(), listedItem{name: firewall.Name, created: firewall.CreationTimestamp}) {
+				continue
+			}
+			c.resourceMap.Store(firewall.Name, nil)
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-
-	for _, firewall := range firewallList.Items {
-		c.resourceMap.Store(firewall.Name, nil)
-	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
@@ -77,42 +75,34 @@ func (c *ComputeFirewalls) Dependencies() []string {
 }
 
 // Remove -
-func (c *ComputeFirewalls) Remove() error {
+func (c *ComputeFirewalls) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		firewallID := key.(string)
 
 		// Parallel firewall deletion
-		errs.Go(func() error {
+		errs.Go(firewallID, "global", func(ctx context.Context) error {
 			deleteCall := c.serviceClient.Firewalls.Delete(c.base.config.Project, firewallID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v] (%v seconds)", firewallID, c.Name(), c.base.config.Project, seconds)
-
+			description := fmt.Sprintf("%v [type: %v project: %v]", firewallID, c.Name(), c.base.config.Project)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.GlobalOperations.Get(c.base.config.Project, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v] (%v seconds)", firewallID, c.Name(), c.base.config.Project, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(firewallID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v] (%v seconds)", firewallID, c.Name(), c.base.config.Project, seconds)
 			return nil
 		})
 		return true
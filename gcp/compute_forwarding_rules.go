@@ -0,0 +1,106 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeForwardingRules - regional forwarding rules, the first part of a load balancer to be removed
+type ComputeForwardingRules struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeForwardingRules{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeForwardingRules
+func (c *ComputeForwardingRules) Name() string {
+	return "ComputeForwardingRules"
+}
+
+// ToSlice - Name of the resourceLister for ComputeForwardingRules
+func (c *ComputeForwardingRules) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeForwardingRules) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeForwardingRules
+func (c *ComputeForwardingRules) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	for _, region := range c.base.config.Regions {
+		ruleListCall := c.serviceClient.ForwardingRules.List(c.base.config.Project, region)
+		err := ruleListCall.Pages(ctx, func(ruleList *compute.ForwardingRuleList) error {
+			for _, rule := range ruleList.Items {
+				if c.base.skip(c.Name(), listedItem{name: rule.Name, labels: rule.Labels, created: rule.CreationTimestamp, monthlyCost: c.base.prices().Monthly(c.base.prices().ForwardingRule, region)}) {
+					continue
+				}
+				c.resourceMap.Store(rule.Name, region)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeForwardingRules) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *ComputeForwardingRules) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		ruleID := key.(string)
+		region := value.(string)
+
+		// Parallel forwarding rule deletion
+		errs.Go(ruleID, region, func(ctx context.Context) error {
+			deleteCall := c.serviceClient.ForwardingRules.Delete(c.base.config.Project, region, ruleID)
+			operation, err := deleteCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v region: %v]", ruleID, c.Name(), c.base.config.Project, region)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), region, operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(ruleID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
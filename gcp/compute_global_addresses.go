@@ -0,0 +1,107 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeGlobalAddresses - global static addresses, removed once the global forwarding rules using them are gone
+// Ranges reserved for private service access (purpose VPC_PEERING) go before the network peerings and networks they were reserved in
+type ComputeGlobalAddresses struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeGlobalAddresses{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeGlobalAddresses
+func (c *ComputeGlobalAddresses) Name() string {
+	return "ComputeGlobalAddresses"
+}
+
+// ToSlice - Name of the resourceLister for ComputeGlobalAddresses
+func (c *ComputeGlobalAddresses) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeGlobalAddresses) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeGlobalAddresses
+func (c *ComputeGlobalAddresses) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	addressListCall := c.serviceClient.GlobalAddresses.List(c.base.config.Project)
+	err := addressListCall.Pages(ctx, func(addressList *compute.AddressList) error {
+		for _, address := range addressList.Items {
+			if c.base.skip(c.Name(), listedItem{name: address.Name, labels: address.Labels, created: address.CreationTimestamp, monthlyCost: addressCost(c.base.prices(), address, "")}) {
+				continue
+			}
+			c.resourceMap.Store(address.Name, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeGlobalAddresses) Dependencies() []string {
+	a := ComputeGlobalForwardingRules{}
+	// Cloud SQL instances with a private IP hold their private service access range
+	b := SQLInstances{}
+	return []string{a.Name(), b.Name()}
+}
+
+// Remove -
+func (c *ComputeGlobalAddresses) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		addressID := key.(string)
+
+		// Parallel address deletion
+		errs.Go(addressID, "global", func(ctx context.Context) error {
+			deleteCall := c.serviceClient.GlobalAddresses.Delete(c.base.config.Project, addressID)
+			operation, err := deleteCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v]", addressID, c.Name(), c.base.config.Project)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), "", operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(addressID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
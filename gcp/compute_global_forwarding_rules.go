@@ -0,0 +1,103 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeGlobalForwardingRules - global forwarding rules, the first part of a load balancer to be removed
+type ComputeGlobalForwardingRules struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeGlobalForwardingRules{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeGlobalForwardingRules
+func (c *ComputeGlobalForwardingRules) Name() string {
+	return "ComputeGlobalForwardingRules"
+}
+
+// ToSlice - Name of the resourceLister for ComputeGlobalForwardingRules
+func (c *ComputeGlobalForwardingRules) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeGlobalForwardingRules) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeGlobalForwardingRules
+func (c *ComputeGlobalForwardingRules) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	ruleListCall := c.serviceClient.GlobalForwardingRules.List(c.base.config.Project)
+	err := ruleListCall.Pages(ctx, func(ruleList *compute.ForwardingRuleList) error {
+		for _, rule := range ruleList.Items {
+			if c.base.skip(c.Name(), listedItem{name: rule.Name, labels: rule.Labels, created: rule.CreationTimestamp, monthlyCost: c.base.prices().Monthly(c.base.prices().ForwardingRule, "")}) {
+				continue
+			}
+			c.resourceMap.Store(rule.Name, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeGlobalForwardingRules) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *ComputeGlobalForwardingRules) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		ruleID := key.(string)
+
+		// Parallel forwarding rule deletion
+		errs.Go(ruleID, "global", func(ctx context.Context) error {
+			deleteCall := c.serviceClient.GlobalForwardingRules.Delete(c.base.config.Project, ruleID)
+			operation, err := deleteCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v]", ruleID, c.Name(), c.base.config.Project)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), "", operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(ruleID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -0,0 +1,169 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeHealthChecks - global and regional health checks, and the legacy http(s) health checks of target pools
+// The last part of a load balancer to be removed, once the backend services, target pools and autohealing instance groups using them are gone
+type ComputeHealthChecks struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+// healthCheckProperties -
+type healthCheckProperties struct {
+	region string
+	// legacy - httpHealthChecks or httpsHealthChecks for legacy health checks, empty otherwise
+	legacy string
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeHealthChecks{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeHealthChecks
+func (c *ComputeHealthChecks) Name() string {
+	return "ComputeHealthChecks"
+}
+
+// ToSlice - Name of the resourceLister for ComputeHealthChecks
+func (c *ComputeHealthChecks) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeHealthChecks) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeHealthChecks, global ones by name, regional ones by region/name
+// and legacy ones by httpHealthChecks/name or httpsHealthChecks/name
+func (c *ComputeHealthChecks) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	store := func(key, created string, healthCheckResource healthCheckProperties) {
+		if c.base.skip(c.Name(), listedItem{name: key, created: created}) {
+			return
+		}
+		c.resourceMap.Store(key, healthCheckResource)
+	}
+
+	healthCheckListCall := c.serviceClient.HealthChecks.List(c.base.config.Project)
+	err := healthCheckListCall.Pages(ctx, func(healthCheckList *compute.HealthCheckList) error {
+		for _, healthCheck := range healthCheckList.Items {
+			store(healthCheck.Name, healthCheck.CreationTimestamp, healthCheckProperties{})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, region := range c.base.config.Regions {
+		regionListCall := c.serviceClient.RegionHealthChecks.List(c.base.config.Project, region)
+		err := regionListCall.Pages(ctx, func(healthCheckList *compute.HealthCheckList) error {
+			for _, healthCheck := range healthCheckList.Items {
+				store(regionalKey(region, healthCheck.Name), healthCheck.CreationTimestamp, healthCheckProperties{region: region})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	httpListCall := c.serviceClient.HttpHealthChecks.List(c.base.config.Project)
+	err = httpListCall.Pages(ctx, func(healthCheckList *compute.HttpHealthCheckList) error {
+		for _, healthCheck := range healthCheckList.Items {
+			store("httpHealthChecks/"+healthCheck.Name, healthCheck.CreationTimestamp, healthCheckProperties{legacy: "httpHealthChecks"})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	httpsListCall := c.serviceClient.HttpsHealthChecks.List(c.base.config.Project)
+	err = httpsListCall.Pages(ctx, func(healthCheckList *compute.HttpsHealthCheckList) error {
+		for _, healthCheck := range healthCheckList.Items {
+			store("httpsHealthChecks/"+healthCheck.Name, healthCheck.CreationTimestamp, healthCheckProperties{legacy: "httpsHealthChecks"})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeHealthChecks) Dependencies() []string {
+	a := ComputeBackendServices{}
+	b := ComputeTargetPools{}
+	d := ComputeInstanceGroupsZone{}
+	e := ComputeInstanceGroupsRegion{}
+	return []string{a.Name(), b.Name(), d.Name(), e.Name()}
+}
+
+// Remove -
+func (c *ComputeHealthChecks) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		healthCheckID := key.(string)
+		healthCheckResource := value.(healthCheckProperties)
+		location := healthCheckResource.region
+		if location == "" {
+			location = "global"
+		}
+
+		// Parallel health check deletion
+		errs.Go(healthCheckID, location, func(ctx context.Context) error {
+			name := keyName(healthCheckID)
+			var operation *compute.Operation
+			var err error
+			switch {
+			case healthCheckResource.legacy == "httpHealthChecks":
+				operation, err = c.serviceClient.HttpHealthChecks.Delete(c.base.config.Project, name).Context(ctx).Do()
+			case healthCheckResource.legacy == "httpsHealthChecks":
+				operation, err = c.serviceClient.HttpsHealthChecks.Delete(c.base.config.Project, name).Context(ctx).Do()
+			case healthCheckResource.region != "":
+				operation, err = c.serviceClient.RegionHealthChecks.Delete(c.base.config.Project, healthCheckResource.region, name).Context(ctx).Do()
+			default:
+				operation, err = c.serviceClient.HealthChecks.Delete(c.base.config.Project, name).Context(ctx).Do()
+			}
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v location: %v]", healthCheckID, c.Name(), c.base.config.Project, location)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), healthCheckResource.region, operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(healthCheckID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
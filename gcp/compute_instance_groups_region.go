@@ -1,14 +1,12 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -21,14 +19,9 @@ type ComputeInstanceGroupsRegion struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeInstanceGroupsRegion{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeInstanceGroupsRegion{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeInstanceGroupsRegion
@@ -43,79 +36,83 @@ func (c *ComputeInstanceGroupsRegion) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeInstanceGroupsRegion) Setup(config config.Config) {
+func (c *ComputeInstanceGroupsRegion) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeInstanceGroupsRegion
-func (c *ComputeInstanceGroupsRegion) List(refreshCache bool) []string {
+func (c *ComputeInstanceGroupsRegion) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
+	templates := make(map[string]*compute.InstanceTemplate)
 	for _, region := range c.base.config.Regions {
 		instanceListCall := c.serviceClient.RegionInstanceGroupManagers.List(c.base.config.Project, region)
-		instanceList, err := instanceListCall.Do()
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		for _, instance := range instanceList.Items {
-			instanceResource := DefaultResourceProperties{
-				region: region,
+		err := instanceListCall.Pages(ctx, func(instanceList *compute.RegionInstanceGroupManagerList) error {
+			for _, instance := range instanceList.Items {
+				if c.base.skip(c.Name(), listedItem{
+					name:        instance.Name,
+					created:     instance.CreationTimestamp,
+					monthlyCost: c.base.groupCost(ctx, c.serviceClient, templates, instance.InstanceTemplate, instance.TargetSize, region),
+				}) {
+					continue
+				}
+				instanceResource := DefaultResourceProperties{
+					region: region,
+				}
+				c.resourceMap.Store(instance.Name, instanceResource)
 			}
-			c.resourceMap.Store(instance.Name, instanceResource)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
 func (c *ComputeInstanceGroupsRegion) Dependencies() []string {
 	a := ComputeRegionAutoScalers{}
-	return []string{a.Name()}
+	b := ComputeBackendServices{}
+	return []string{a.Name(), b.Name()}
 }
 
 // Remove -
-func (c *ComputeInstanceGroupsRegion) Remove() error {
+func (c *ComputeInstanceGroupsRegion) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		instanceID := key.(string)
 		region := value.(DefaultResourceProperties).region
 
 		// Parallel instance deletion
-		errs.Go(func() error {
+		errs.Go(instanceID, region, func(ctx context.Context) error {
 			deleteCall := c.serviceClient.RegionInstanceGroupManagers.Delete(c.base.config.Project, region, instanceID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v region: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, region, seconds)
+			description := fmt.Sprintf("%v [type: %v project: %v region: %v]", instanceID, c.Name(), c.base.config.Project, region)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.RegionOperations.Get(c.base.config.Project, region, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v region: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, region, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(instanceID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v region: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, region, seconds)
 			return nil
 		})
 		return true
@@ -1,15 +1,13 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
-	"reflect"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
+	"github.com/arehmandev/gcp-nuke/pricing"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -24,14 +22,9 @@ type ComputeInstanceGroupsZone struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeInstanceGroupsZone{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeInstanceGroupsZone{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeInstanceGroupsZone
@@ -46,92 +39,103 @@ func (c *ComputeInstanceGroupsZone) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeInstanceGroupsZone) Setup(config config.Config) {
+func (c *ComputeInstanceGroupsZone) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	if err != nil {
+		return err
+	}
 
-	// Get the node pool list with some reflection rather than re-instantiating
-	a := ContainerGKEClusters{}
-	gkeResource := resourceMap[a.Name()]
-	gkeInstance := reflect.ValueOf(gkeResource).Elem().Addr().Interface().(*ContainerGKEClusters)
-	gkeInstance.Setup(config)
-	gkeInstance.List(true)
+	// Get the node pool list, instantiating is cheap now that the api clients are shared
+	gkeInstance := ContainerGKEClusters{}
+	if err := gkeInstance.Setup(config, clients); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(config.Context, config.TimeoutFor(gkeInstance.Name()))
+	defer cancel()
+	if _, err := gkeInstance.List(ctx, true); err != nil {
+		return err
+	}
 	c.gkeInstanceGroups = gkeInstance.InstanceGroups
+	return nil
 }
 
 // List - Returns a list of all ComputeInstanceGroupsZone
-func (c *ComputeInstanceGroupsZone) List(refreshCache bool) []string {
+func (c *ComputeInstanceGroupsZone) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
+	templates := make(map[string]*compute.InstanceTemplate)
 	for _, zone := range c.base.config.Zones {
 		instanceListCall := c.serviceClient.InstanceGroupManagers.List(c.base.config.Project, zone)
-		instanceList, err := instanceListCall.Do()
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		for _, instance := range instanceList.Items {
+		err := instanceListCall.Pages(ctx, func(instanceList *compute.InstanceGroupManagerList) error {
+			for _, instance := range instanceList.Items {
 
-			if helpers.SliceContains(c.gkeInstanceGroups, instance.Name) {
-				continue
-			}
+				if helpers.SliceContains(c.gkeInstanceGroups, instance.Name) {
+					continue
+				}
 
-			instanceResource := DefaultResourceProperties{
-				zone: zone,
+				if c.base.skip(c.Name(), listedItem{
+					name:        instance.Name,
+					created:     instance.CreationTimestamp,
+					monthlyCost: c.base.groupCost(ctx, c.serviceClient, templates, instance.InstanceTemplate, instance.TargetSize, pricing.Region(zone)),
+				}) {
+					continue
+				}
+				instanceResource := DefaultResourceProperties{
+					zone: zone,
+				}
+				c.resourceMap.Store(instance.Name, instanceResource)
 			}
-			c.resourceMap.Store(instance.Name, instanceResource)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
 func (c *ComputeInstanceGroupsZone) Dependencies() []string {
 	a := ComputeZoneAutoScalers{}
-	return []string{a.Name()}
+	b := ComputeBackendServices{}
+	return []string{a.Name(), b.Name()}
 }
 
 // Remove -
-func (c *ComputeInstanceGroupsZone) Remove() error {
+func (c *ComputeInstanceGroupsZone) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		instanceID := key.(string)
 		zone := value.(DefaultResourceProperties).zone
 
 		// Parallel instance deletion
-		errs.Go(func() error {
+		errs.Go(instanceID, zone, func(ctx context.Context) error {
 			deleteCall := c.serviceClient.InstanceGroupManagers.Delete(c.base.config.Project, zone, instanceID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, seconds)
-
+			description := fmt.Sprintf("%v [type: %v project: %v zone: %v]", instanceID, c.Name(), c.base.config.Project, zone)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.ZoneOperations.Get(c.base.config.Project, zone, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(instanceID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, seconds)
 			return nil
 		})
 		return true
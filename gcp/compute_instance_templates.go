@@ -1,14 +1,12 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -21,14 +19,9 @@ type ComputeInstanceTemplates struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeInstanceTemplates{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeInstanceTemplates{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeInstanceTemplates
@@ -43,30 +36,40 @@ func (c *ComputeInstanceTemplates) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeInstanceTemplates) Setup(config config.Config) {
+func (c *ComputeInstanceTemplates) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeInstanceTemplates
-func (c *ComputeInstanceTemplates) List(refreshCache bool) []string {
+func (c *ComputeInstanceTemplates) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	instanceListCall := c.serviceClient.InstanceTemplates.List(c.base.config.Project)
-	instanceList, err := instanceListCall.Do()
+	err := instanceListCall.Pages(ctx, func(instanceList *compute.InstanceTemplateList) error {
+		for _, instance := range instanceList.Items {
+			// Templates have no labels of their own, the labels of their instances are used instead
+			var labels map[string]string
+			if instance.Properties != nil {
+				labels = instance.Properties.Labels
+			}
+			if c.base.skip(c.Name(), listedItem{name: instance.Name, labels: labels, created: instance.CreationTimestamp}) {
+				continue
+			}
+			instanceResource := DefaultResourceProperties{}
+			c.resourceMap.Store(instance.Name, instanceResource)
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatal(err)
-	}
-
-	for _, instance := range instanceList.Items {
-		instanceResource := DefaultResourceProperties{}
-		c.resourceMap.Store(instance.Name, instanceResource)
+		return nil, err
 	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
@@ -78,41 +81,34 @@ func (c *ComputeInstanceTemplates) Dependencies() []string {
 }
 
 // Remove -
-func (c *ComputeInstanceTemplates) Remove() error {
+func (c *ComputeInstanceTemplates) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		instanceID := key.(string)
 
 		// Parallel instance deletion
-		errs.Go(func() error {
+		errs.Go(instanceID, "global", func(ctx context.Context) error {
 			deleteCall := c.serviceClient.InstanceTemplates.Delete(c.base.config.Project, instanceID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, seconds)
+			description := fmt.Sprintf("%v [type: %v project: %v]", instanceID, c.Name(), c.base.config.Project)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.GlobalOperations.Get(c.base.config.Project, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(instanceID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, seconds)
 			return nil
 		})
 		return true
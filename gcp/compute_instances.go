@@ -1,15 +1,13 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -22,14 +20,9 @@ type ComputeInstances struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeInstances{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeInstances{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeInstances
@@ -44,45 +37,60 @@ func (c *ComputeInstances) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeInstances) Setup(config config.Config) {
+func (c *ComputeInstances) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeInstances
-func (c *ComputeInstances) List(refreshCache bool) []string {
+func (c *ComputeInstances) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	for _, zone := range c.base.config.Zones {
 		instanceListCall := c.serviceClient.Instances.List(c.base.config.Project, zone)
-		instanceList, err := instanceListCall.Do()
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		for _, instance := range instanceList.Items {
-			skipInstance := false
-			// Skip any managed by instance groups
-			for _, item := range instance.Metadata.Items {
-				if item.Key == "created-by" && strings.Contains(*item.Value, "/instanceGroupManagers/") {
-					skipInstance = true
+		err := instanceListCall.Pages(ctx, func(instanceList *compute.InstanceList) error {
+			for _, instance := range instanceList.Items {
+				skipInstance := false
+				createdBy := ""
+				// Skip any managed by instance groups
+				for _, item := range instance.Metadata.Items {
+					if item.Key == "created-by" && item.Value != nil {
+						createdBy = *item.Value
+					}
+					if item.Key == "created-by" && strings.Contains(*item.Value, "/instanceGroupManagers/") {
+						skipInstance = true
+					}
+				}
+				if skipInstance {
+					continue
 				}
-			}
-			if skipInstance {
-				continue
-			}
 
-			instanceResource := DefaultResourceProperties{
-				zone: zone,
+				if c.base.skip(c.Name(), listedItem{
+					name:        instance.Name,
+					labels:      instance.Labels,
+					created:     instance.CreationTimestamp,
+					createdBy:   createdBy,
+					monthlyCost: instanceCost(c.base.prices(), instance, zone),
+				}) {
+					continue
+				}
+				instanceResource := DefaultResourceProperties{
+					zone: zone,
+				}
+				c.resourceMap.Store(instance.Name, instanceResource)
 			}
-			c.resourceMap.Store(instance.Name, instanceResource)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
@@ -91,57 +99,57 @@ func (c *ComputeInstances) Dependencies() []string {
 }
 
 // Remove -
-func (c *ComputeInstances) Remove() error {
+func (c *ComputeInstances) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		instanceID := key.(string)
 		zone := value.(DefaultResourceProperties).zone
 
 		// Parallel instance deletion
-		errs.Go(func() error {
+		errs.Go(instanceID, zone, func(ctx context.Context) error {
 			getInstanceCall := c.serviceClient.Instances.Get(c.base.config.Project, zone, instanceID)
-			getOp, err := getInstanceCall.Do()
+			getOp, err := getInstanceCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
 			for _, disk := range getOp.Disks {
+				// Local SSDs can't be snapshotted
+				if disk.Type == "PERSISTENT" && disk.Source != "" {
+					// Regional persistent disks are in the region of the instance, not its zone
+					location, diskName := diskOfSource(disk.Source)
+					if err := c.base.snapshotDisk(ctx, c.serviceClient, c.Name(), location, diskName); err != nil {
+						return err
+					}
+				}
 				// Set all attached compute disks to auto delete on instance deletion
 				diskSetCall := c.serviceClient.Instances.SetDiskAutoDelete(c.base.config.Project, zone, instanceID, true, disk.DeviceName)
 				// Todo - check this op until it completes, most likely not needed, but always nice to be safe
-				_, err := diskSetCall.Do()
+				_, err := diskSetCall.Context(ctx).Do()
 				if err != nil {
 					return err
 				}
 			}
 			deleteCall := c.serviceClient.Instances.Delete(c.base.config.Project, zone, instanceID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, seconds)
-
+			description := fmt.Sprintf("%v [type: %v project: %v zone: %v]", instanceID, c.Name(), c.base.config.Project, zone)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.ZoneOperations.Get(c.base.config.Project, zone, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(instanceID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, seconds)
 			return nil
 		})
 
@@ -1,14 +1,12 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -21,14 +19,9 @@ type ComputeNetworkPeerings struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeNetworkPeerings{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeNetworkPeerings{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeNetworkPeerings
@@ -43,31 +36,36 @@ func (c *ComputeNetworkPeerings) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeNetworkPeerings) Setup(config config.Config) {
+func (c *ComputeNetworkPeerings) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeNetworkPeerings
-func (c *ComputeNetworkPeerings) List(refreshCache bool) []string {
+func (c *ComputeNetworkPeerings) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	networkListCall := c.serviceClient.Networks.List(c.base.config.Project)
-	networkList, err := networkListCall.Do()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	for _, network := range networkList.Items {
-		for _, networkPeering := range network.Peerings {
-			c.resourceMap.Store(networkPeering.Name, network.Name)
+	err := networkListCall.Pages(ctx, func(networkList *compute.NetworkList) error {
+		for _, network := range networkList.Items {
+			for _, networkPeering := range network.Peerings {
+				if c.base.skip(c.Name(), listedItem{name: networkPeering.Name, created: network.CreationTimestamp}) {
+					continue
+				}
+				c.resourceMap.Store(networkPeering.Name, network.Name)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
@@ -75,50 +73,46 @@ func (c *ComputeNetworkPeerings) Dependencies() []string {
 	a := ComputeInstanceGroupsRegion{}
 	b := ComputeInstanceGroupsZone{}
 	cl := ContainerGKEClusters{}
-	return []string{a.Name(), b.Name(), cl.Name()}
+	// Private service access ranges (purpose VPC_PEERING) are released before the peering to the service producer goes
+	d := ComputeGlobalAddresses{}
+	// Cloud SQL instances with a private IP sit in the service producer network at the other end of the peering
+	e := SQLInstances{}
+	return []string{a.Name(), b.Name(), cl.Name(), d.Name(), e.Name()}
 }
 
 // Remove -
-func (c *ComputeNetworkPeerings) Remove() error {
+func (c *ComputeNetworkPeerings) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		networkPeeringID := key.(string)
 		networkID := value.(string)
 
 		// Parallel network deletion
-		errs.Go(func() error {
+		errs.Go(networkPeeringID, "global", func(ctx context.Context) error {
 
 			deleteCall := c.serviceClient.Networks.RemovePeering(c.base.config.Project, networkID, &compute.NetworksRemovePeeringRequest{
 				Name: networkPeeringID,
 			})
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v] (%v seconds)", networkID, c.Name(), c.base.config.Project, seconds)
-
+			description := fmt.Sprintf("%v [type: %v project: %v]", networkID, c.Name(), c.base.config.Project)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.GlobalOperations.Get(c.base.config.Project, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v] (%v seconds)", networkID, c.Name(), c.base.config.Project, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
-			c.resourceMap.Delete(networkID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v] (%v seconds)", networkID, c.Name(), c.base.config.Project, seconds)
+			c.resourceMap.Delete(networkPeeringID)
 			return nil
 		})
 		return true
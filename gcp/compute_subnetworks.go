@@ -1,14 +1,12 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -21,14 +19,9 @@ type ComputeSubnetworks struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeSubnetworks{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeSubnetworks{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeSubnetworks
@@ -43,31 +36,36 @@ func (c *ComputeSubnetworks) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeSubnetworks) Setup(config config.Config) {
+func (c *ComputeSubnetworks) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeSubnetworks
-func (c *ComputeSubnetworks) List(refreshCache bool) []string {
+func (c *ComputeSubnetworks) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	for _, region := range c.base.config.Regions {
 		subnetworkListCall := c.serviceClient.Subnetworks.List(c.base.config.Project, region)
-		subnetworkList, err := subnetworkListCall.Do()
+		err := subnetworkListCall.Pages(ctx, func(subnetworkList *compute.SubnetworkList) error {
+			for _, subnetwork := range subnetworkList.Items {
+				if c.base.skip(c.Name(), listedItem{name: subnetwork.Name, created: subnetwork.CreationTimestamp}) {
+					continue
+				}
+				c.resourceMap.Store(subnetwork.Name, region)
+			}
+			return nil
+		})
 		if err != nil {
-			log.Fatal(err)
-		}
-
-		for _, subnetwork := range subnetworkList.Items {
-			c.resourceMap.Store(subnetwork.Name, region)
+			return nil, err
 		}
 	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
@@ -75,47 +73,45 @@ func (c *ComputeSubnetworks) Dependencies() []string {
 	a := ComputeInstanceGroupsRegion{}
 	b := ComputeInstanceGroupsZone{}
 	cl := ContainerGKEClusters{}
-	return []string{a.Name(), b.Name(), cl.Name()}
+	f := ComputeForwardingRules{}
+	d := ComputeAddresses{}
+	e := CloudRunServices{}
+	g := CloudRunJobs{}
+	h := CloudFunctions{}
+	i := VPCAccessConnectors{}
+	return []string{a.Name(), b.Name(), cl.Name(), f.Name(), d.Name(), e.Name(), g.Name(), h.Name(), i.Name()}
 }
 
 // Remove -
-func (c *ComputeSubnetworks) Remove() error {
+func (c *ComputeSubnetworks) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		subnetworkID := key.(string)
 		region := value.(string)
 
 		// Parallel subnetwork deletion
-		errs.Go(func() error {
+		errs.Go(subnetworkID, region, func(ctx context.Context) error {
 			deleteCall := c.serviceClient.Subnetworks.Delete(c.base.config.Project, region, subnetworkID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v] (%v seconds)", subnetworkID, c.Name(), c.base.config.Project, seconds)
-
+			description := fmt.Sprintf("%v [type: %v project: %v]", subnetworkID, c.Name(), c.base.config.Project)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.RegionOperations.Get(c.base.config.Project, region, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v] (%v seconds)", subnetworkID, c.Name(), c.base.config.Project, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(subnetworkID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v] (%v seconds)", subnetworkID, c.Name(), c.base.config.Project, seconds)
 			return nil
 		})
 		return true
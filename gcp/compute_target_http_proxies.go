@@ -0,0 +1,131 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeTargetHTTPProxies - global and regional target http proxies, removed once their forwarding rules are gone
+type ComputeTargetHTTPProxies struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeTargetHTTPProxies{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeTargetHTTPProxies
+func (c *ComputeTargetHTTPProxies) Name() string {
+	return "ComputeTargetHTTPProxies"
+}
+
+// ToSlice - Name of the resourceLister for ComputeTargetHTTPProxies
+func (c *ComputeTargetHTTPProxies) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeTargetHTTPProxies) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeTargetHTTPProxies, global ones by name and regional ones by region/name
+func (c *ComputeTargetHTTPProxies) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	store := func(proxy *compute.TargetHttpProxy, region string) {
+		if c.base.skip(c.Name(), listedItem{name: regionalKey(region, proxy.Name), created: proxy.CreationTimestamp}) {
+			return
+		}
+		c.resourceMap.Store(regionalKey(region, proxy.Name), region)
+	}
+
+	proxyListCall := c.serviceClient.TargetHttpProxies.List(c.base.config.Project)
+	err := proxyListCall.Pages(ctx, func(proxyList *compute.TargetHttpProxyList) error {
+		for _, proxy := range proxyList.Items {
+			store(proxy, "")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, region := range c.base.config.Regions {
+		regionListCall := c.serviceClient.RegionTargetHttpProxies.List(c.base.config.Project, region)
+		err := regionListCall.Pages(ctx, func(proxyList *compute.TargetHttpProxyList) error {
+			for _, proxy := range proxyList.Items {
+				store(proxy, region)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeTargetHTTPProxies) Dependencies() []string {
+	a := ComputeGlobalForwardingRules{}
+	b := ComputeForwardingRules{}
+	return []string{a.Name(), b.Name()}
+}
+
+// Remove -
+func (c *ComputeTargetHTTPProxies) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		proxyID := key.(string)
+		region := value.(string)
+		location := region
+		if region == "" {
+			location = "global"
+		}
+
+		// Parallel proxy deletion
+		errs.Go(proxyID, location, func(ctx context.Context) error {
+			var operation *compute.Operation
+			var err error
+			if region == "" {
+				operation, err = c.serviceClient.TargetHttpProxies.Delete(c.base.config.Project, keyName(proxyID)).Context(ctx).Do()
+			} else {
+				operation, err = c.serviceClient.RegionTargetHttpProxies.Delete(c.base.config.Project, region, keyName(proxyID)).Context(ctx).Do()
+			}
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v location: %v]", keyName(proxyID), c.Name(), c.base.config.Project, location)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), region, operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(proxyID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
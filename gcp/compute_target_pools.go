@@ -0,0 +1,109 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeTargetPools - target pools of network load balancers, removed once their forwarding rules and the instance groups adding instances to them are gone
+type ComputeTargetPools struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeTargetPools{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeTargetPools
+func (c *ComputeTargetPools) Name() string {
+	return "ComputeTargetPools"
+}
+
+// ToSlice - Name of the resourceLister for ComputeTargetPools
+func (c *ComputeTargetPools) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeTargetPools) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeTargetPools
+func (c *ComputeTargetPools) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	for _, region := range c.base.config.Regions {
+		poolListCall := c.serviceClient.TargetPools.List(c.base.config.Project, region)
+		err := poolListCall.Pages(ctx, func(poolList *compute.TargetPoolList) error {
+			for _, pool := range poolList.Items {
+				if c.base.skip(c.Name(), listedItem{name: pool.Name, created: pool.CreationTimestamp}) {
+					continue
+				}
+				c.resourceMap.Store(pool.Name, region)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeTargetPools) Dependencies() []string {
+	a := ComputeForwardingRules{}
+	b := ComputeInstanceGroupsZone{}
+	d := ComputeInstanceGroupsRegion{}
+	return []string{a.Name(), b.Name(), d.Name()}
+}
+
+// Remove -
+func (c *ComputeTargetPools) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		poolID := key.(string)
+		region := value.(string)
+
+		// Parallel target pool deletion
+		errs.Go(poolID, region, func(ctx context.Context) error {
+			deleteCall := c.serviceClient.TargetPools.Delete(c.base.config.Project, region, poolID)
+			operation, err := deleteCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v region: %v]", poolID, c.Name(), c.base.config.Project, region)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), region, operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(poolID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
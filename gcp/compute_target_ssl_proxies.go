@@ -0,0 +1,104 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeTargetSSLProxies - target ssl proxies, removed once their forwarding rules are gone
+type ComputeTargetSSLProxies struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeTargetSSLProxies{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeTargetSSLProxies
+func (c *ComputeTargetSSLProxies) Name() string {
+	return "ComputeTargetSSLProxies"
+}
+
+// ToSlice - Name of the resourceLister for ComputeTargetSSLProxies
+func (c *ComputeTargetSSLProxies) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeTargetSSLProxies) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeTargetSSLProxies
+func (c *ComputeTargetSSLProxies) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	proxyListCall := c.serviceClient.TargetSslProxies.List(c.base.config.Project)
+	err := proxyListCall.Pages(ctx, func(proxyList *compute.TargetSslProxyList) error {
+		for _, proxy := range proxyList.Items {
+			if c.base.skip(c.Name(), listedItem{name: proxy.Name, created: proxy.CreationTimestamp}) {
+				continue
+			}
+			c.resourceMap.Store(proxy.Name, nil)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeTargetSSLProxies) Dependencies() []string {
+	a := ComputeGlobalForwardingRules{}
+	return []string{a.Name()}
+}
+
+// Remove -
+func (c *ComputeTargetSSLProxies) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		proxyID := key.(string)
+
+		// Parallel proxy deletion
+		errs.Go(proxyID, "global", func(ctx context.Context) error {
+			deleteCall := c.serviceClient.TargetSslProxies.Delete(c.base.config.Project, proxyID)
+			operation, err := deleteCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v]", proxyID, c.Name(), c.base.config.Project)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), "", operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(proxyID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -0,0 +1,131 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/compute/v1"
+)
+
+// ComputeURLMaps - global and regional url maps, removed once the http(s) proxies using them are gone
+type ComputeURLMaps struct {
+	serviceClient *compute.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &ComputeURLMaps{}
+	})
+}
+
+// Name - Name of the resourceLister for ComputeURLMaps
+func (c *ComputeURLMaps) Name() string {
+	return "ComputeURLMaps"
+}
+
+// ToSlice - Name of the resourceLister for ComputeURLMaps
+func (c *ComputeURLMaps) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *ComputeURLMaps) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Compute()
+	return err
+}
+
+// List - Returns a list of all ComputeURLMaps, global ones by name and regional ones by region/name
+func (c *ComputeURLMaps) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	store := func(urlMap *compute.UrlMap, region string) {
+		if c.base.skip(c.Name(), listedItem{name: regionalKey(region, urlMap.Name), created: urlMap.CreationTimestamp}) {
+			return
+		}
+		c.resourceMap.Store(regionalKey(region, urlMap.Name), region)
+	}
+
+	urlMapListCall := c.serviceClient.UrlMaps.List(c.base.config.Project)
+	err := urlMapListCall.Pages(ctx, func(urlMapList *compute.UrlMapList) error {
+		for _, urlMap := range urlMapList.Items {
+			store(urlMap, "")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, region := range c.base.config.Regions {
+		regionListCall := c.serviceClient.RegionUrlMaps.List(c.base.config.Project, region)
+		err := regionListCall.Pages(ctx, func(urlMapList *compute.UrlMapList) error {
+			for _, urlMap := range urlMapList.Items {
+				store(urlMap, region)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *ComputeURLMaps) Dependencies() []string {
+	a := ComputeTargetHTTPProxies{}
+	b := ComputeTargetHTTPSProxies{}
+	return []string{a.Name(), b.Name()}
+}
+
+// Remove -
+func (c *ComputeURLMaps) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		urlMapID := key.(string)
+		region := value.(string)
+		location := region
+		if region == "" {
+			location = "global"
+		}
+
+		// Parallel url map deletion
+		errs.Go(urlMapID, location, func(ctx context.Context) error {
+			var operation *compute.Operation
+			var err error
+			if region == "" {
+				operation, err = c.serviceClient.UrlMaps.Delete(c.base.config.Project, keyName(urlMapID)).Context(ctx).Do()
+			} else {
+				operation, err = c.serviceClient.RegionUrlMaps.Delete(c.base.config.Project, region, keyName(urlMapID)).Context(ctx).Do()
+			}
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v location: %v]", keyName(urlMapID), c.Name(), c.base.config.Project, location)
+			err = c.base.waitForComputeOperation(ctx, c.serviceClient, c.Name(), region, operation.Name, description)
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(urlMapID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
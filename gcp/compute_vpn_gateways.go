@@ -1,14 +1,12 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -21,14 +19,9 @@ type ComputeVPNGateways struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeVPNGateways{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeVPNGateways{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeVPNGateways
@@ -43,31 +36,36 @@ func (c *ComputeVPNGateways) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeVPNGateways) Setup(config config.Config) {
+func (c *ComputeVPNGateways) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeVPNGateways
-func (c *ComputeVPNGateways) List(refreshCache bool) []string {
+func (c *ComputeVPNGateways) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	for _, region := range c.base.config.Regions {
 		gatewayListCall := c.serviceClient.VpnGateways.List(c.base.config.Project, region)
-		gatewayList, err := gatewayListCall.Do()
+		err := gatewayListCall.Pages(ctx, func(gatewayList *compute.VpnGatewayList) error {
+			for _, gateway := range gatewayList.Items {
+				if c.base.skip(c.Name(), listedItem{name: gateway.Name, labels: gateway.Labels, created: gateway.CreationTimestamp}) {
+					continue
+				}
+				c.resourceMap.Store(gateway.Name, region)
+			}
+			return nil
+		})
 		if err != nil {
-			log.Fatal(err)
-		}
-
-		for _, gateway := range gatewayList.Items {
-			c.resourceMap.Store(gateway.Name, region)
+			return nil, err
 		}
 	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
@@ -77,43 +75,35 @@ func (c *ComputeVPNGateways) Dependencies() []string {
 }
 
 // Remove -
-func (c *ComputeVPNGateways) Remove() error {
+func (c *ComputeVPNGateways) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		gatewayID := key.(string)
 		region := value.(string)
 
 		// Parallel gateway deletion
-		errs.Go(func() error {
+		errs.Go(gatewayID, region, func(ctx context.Context) error {
 			deleteCall := c.serviceClient.VpnGateways.Delete(c.base.config.Project, region, gatewayID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v] (%v seconds)", gatewayID, c.Name(), c.base.config.Project, seconds)
-
+			description := fmt.Sprintf("%v [type: %v project: %v]", gatewayID, c.Name(), c.base.config.Project)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.RegionOperations.Get(c.base.config.Project, region, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v] (%v seconds)", gatewayID, c.Name(), c.base.config.Project, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(gatewayID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v] (%v seconds)", gatewayID, c.Name(), c.base.config.Project, seconds)
 			return nil
 		})
 		return true
@@ -1,14 +1,12 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -21,14 +19,9 @@ type ComputeZoneAutoScalers struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeZoneAutoScalers{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeZoneAutoScalers{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeZoneAutoScalers
@@ -43,34 +36,39 @@ func (c *ComputeZoneAutoScalers) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeZoneAutoScalers) Setup(config config.Config) {
+func (c *ComputeZoneAutoScalers) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeZoneAutoScalers
-func (c *ComputeZoneAutoScalers) List(refreshCache bool) []string {
+func (c *ComputeZoneAutoScalers) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	for _, zone := range c.base.config.Zones {
 		instanceListCall := c.serviceClient.Autoscalers.List(c.base.config.Project, zone)
-		instanceList, err := instanceListCall.Do()
-		if err != nil {
-			log.Fatal(err)
-		}
-
-		for _, instance := range instanceList.Items {
-			instanceResource := DefaultResourceProperties{
-				zone: zone,
+		err := instanceListCall.Pages(ctx, func(instanceList *compute.AutoscalerList) error {
+			for _, instance := range instanceList.Items {
+				if c.base.skip(c.Name(), listedItem{name: instance.Name, created: instance.CreationTimestamp}) {
+					continue
+				}
+				instanceResource := DefaultResourceProperties{
+					zone: zone,
+				}
+				c.resourceMap.Store(instance.Name, instanceResource)
 			}
-			c.resourceMap.Store(instance.Name, instanceResource)
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
@@ -79,43 +77,35 @@ func (c *ComputeZoneAutoScalers) Dependencies() []string {
 }
 
 // Remove -
-func (c *ComputeZoneAutoScalers) Remove() error {
+func (c *ComputeZoneAutoScalers) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		instanceID := key.(string)
 		zone := value.(DefaultResourceProperties).zone
 
 		// Parallel instance deletion
-		errs.Go(func() error {
+		errs.Go(instanceID, zone, func(ctx context.Context) error {
 			deleteCall := c.serviceClient.Autoscalers.Delete(c.base.config.Project, zone, instanceID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, seconds)
-
+			description := fmt.Sprintf("%v [type: %v project: %v zone: %v]", instanceID, c.Name(), c.base.config.Project, zone)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.ZoneOperations.Get(c.base.config.Project, zone, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(instanceID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v zone: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, zone, seconds)
 			return nil
 		})
 		return true
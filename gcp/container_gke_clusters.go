@@ -1,15 +1,13 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/container/v1"
 )
@@ -23,14 +21,9 @@ type ContainerGKEClusters struct {
 }
 
 func init() {
-	containerService, err := container.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	containerResource := ContainerGKEClusters{
-		serviceClient: containerService,
-	}
-	register(&containerResource)
+	register(func() Resource {
+		return &ContainerGKEClusters{}
+	})
 }
 
 // Name - Name of the resourceLister for ContainerGKEClusters
@@ -45,33 +38,40 @@ func (c *ContainerGKEClusters) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ContainerGKEClusters) Setup(config config.Config) {
+func (c *ContainerGKEClusters) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Container()
+	return err
 }
 
 // List - Returns a list of all ContainerGKEClusters
-func (c *ContainerGKEClusters) List(refreshCache bool) []string {
+func (c *ContainerGKEClusters) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	instanceListCall := c.serviceClient.Projects.Locations.Clusters.List(fmt.Sprintf("projects/%v/locations/-", c.base.config.Project))
-	instanceList, err := instanceListCall.Do()
+	instanceList, err := instanceListCall.Context(ctx).Do()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	for _, instance := range instanceList.Clusters {
-		c.appendInstanceGroups(instance.Name, instance.Location)
-		instanceResource := DefaultResourceProperties{}
+		// Node pools of skipped clusters are still recorded, so their instance groups are never deleted on their own
+		if err := c.appendInstanceGroups(ctx, instance.Name, instance.Location); err != nil {
+			return nil, err
+		}
 		clusterLink := extractGKESelfLink(instance.SelfLink)
+		if c.base.skip(c.Name(), listedItem{name: clusterLink, labels: instance.ResourceLabels, created: instance.CreateTime, monthlyCost: clusterCost(c.base.prices(), instance)}) {
+			continue
+		}
+		instanceResource := DefaultResourceProperties{}
 		c.resourceMap.Store(clusterLink, instanceResource)
 	}
 
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
@@ -80,41 +80,34 @@ func (c *ContainerGKEClusters) Dependencies() []string {
 }
 
 // Remove -
-func (c *ContainerGKEClusters) Remove() error {
+func (c *ContainerGKEClusters) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		instanceID := key.(string)
 		location := strings.Split(instanceID, "/")[3]
 		// Parallel instance deletion
-		errs.Go(func() error {
+		errs.Go(instanceID, location, func(ctx context.Context) error {
 			deleteCall := c.serviceClient.Projects.Locations.Clusters.Delete(instanceID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, seconds)
+			description := fmt.Sprintf("%v [type: %v project: %v]", instanceID, c.Name(), c.base.config.Project)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.Projects.Locations.Operations.Get(fmt.Sprintf("projects/%v/locations/%v/operations/%v", c.base.config.Project, location, operation.Name))
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v] (%v seconds):\n %v", instanceID, c.Name(), c.base.config.Project, c.base.config.Timeout, err.Error())
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(instanceID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v] (%v seconds)", instanceID, c.Name(), c.base.config.Project, seconds)
 			return nil
 		})
 		return true
@@ -125,12 +118,12 @@ func (c *ContainerGKEClusters) Remove() error {
 }
 
 // appendInstanceGroups - keep track of instance groups - this is used by compute_instance_zone_groups to exclude any gke nodepools
-func (c *ContainerGKEClusters) appendInstanceGroups(clusterName, clusterLocation string) {
+func (c *ContainerGKEClusters) appendInstanceGroups(ctx context.Context, clusterName, clusterLocation string) error {
 	parentLocation := fmt.Sprintf("projects/%v/locations/%v/clusters/%v", c.base.config.Project, clusterLocation, clusterName)
 	nodePoolCall := c.serviceClient.Projects.Locations.Clusters.NodePools.List(parentLocation)
-	nodePools, err := nodePoolCall.Do()
+	nodePools, err := nodePoolCall.Context(ctx).Do()
 	if err != nil {
-		log.Fatal((err))
+		return err
 	}
 	for _, nodePool := range nodePools.NodePools {
 		for _, instanceGroupURL := range nodePool.InstanceGroupUrls {
@@ -138,4 +131,5 @@ func (c *ContainerGKEClusters) appendInstanceGroups(clusterName, clusterLocation
 			c.InstanceGroups = append(c.InstanceGroups, instanceGroupName)
 		}
 	}
+	return nil
 }
@@ -0,0 +1,144 @@
+package gcp
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/pricing"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/container/v1"
+	"google.golang.org/api/vpcaccess/v1"
+)
+
+// defaultPrices - the bundled price table, used unless --price-table is set
+var defaultPrices = pricing.Default()
+
+// unpricedMachineTypes - machine types already warned about, so each is only logged once
+var unpricedMachineTypes sync.Map
+
+// prices - the price table of the run
+func (b *ResourceBase) prices() *pricing.Table {
+	if b.config.Prices != nil {
+		return b.config.Prices
+	}
+	return defaultPrices
+}
+
+// machineCost - monthly cost of the machine type, 0 when it isn't in the price table
+func machineCost(prices *pricing.Table, machineType, region string, preemptible bool) float64 {
+	monthly, ok := prices.Machine(machineType, region, preemptible)
+	if !ok {
+		if _, warned := unpricedMachineTypes.LoadOrStore(machineType, true); !warned {
+			log.Printf("[Warning] No price for machine type %v, it is left out of the cost estimate", machineType)
+		}
+	}
+	return monthly
+}
+
+// instanceCost - the machine while it's running, and the disks deleted along with it
+func instanceCost(prices *pricing.Table, instance *compute.Instance, zone string) float64 {
+	region := pricing.Region(zone)
+	monthly := 0.0
+	if instance.Status != "TERMINATED" && instance.Status != "STOPPED" {
+		preemptible := instance.Scheduling != nil && instance.Scheduling.Preemptible
+		monthly += machineCost(prices, instance.MachineType, region, preemptible)
+	}
+	for _, disk := range instance.Disks {
+		// Attached disks don't say their type, so they are priced as the default disk type
+		monthly += prices.Disk(prices.DefaultDiskType, disk.DiskSizeGb, region)
+	}
+	return monthly
+}
+
+// templateCost - a single instance created from the template
+func templateCost(prices *pricing.Table, properties *compute.InstanceProperties, region string) float64 {
+	if properties == nil {
+		return 0
+	}
+	preemptible := properties.Scheduling != nil && properties.Scheduling.Preemptible
+	monthly := machineCost(prices, properties.MachineType, region, preemptible)
+	for _, disk := range properties.Disks {
+		if disk.InitializeParams != nil {
+			monthly += prices.Disk(disk.InitializeParams.DiskType, disk.InitializeParams.DiskSizeGb, region)
+		}
+	}
+	return monthly
+}
+
+// addressCost - reserved external addresses, addresses in use are billed along with whatever uses them and internal ones are free
+func addressCost(prices *pricing.Table, address *compute.Address, region string) float64 {
+	if address.AddressType == "INTERNAL" || address.Status != "RESERVED" {
+		return 0
+	}
+	return prices.Monthly(prices.StaticAddress, region)
+}
+
+// connectorCost - the instances a vpc access connector keeps running at the least, e2-micro and 2 of them unless it says otherwise
+func connectorCost(prices *pricing.Table, connector *vpcaccess.Connector, region string) float64 {
+	machineType := connector.MachineType
+	if machineType == "" {
+		machineType = "e2-micro"
+	}
+	minInstances := connector.MinInstances
+	if minInstances == 0 {
+		minInstances = 2
+	}
+	return float64(minInstances) * machineCost(prices, machineType, region, false)
+}
+
+// clusterCost - the management fee and the nodes of every node pool, autopilot clusters only count the fee as pods are billed on their own
+func clusterCost(prices *pricing.Table, cluster *container.Cluster) float64 {
+	region := pricing.Region(cluster.Location)
+	monthly := prices.Monthly(prices.GKECluster, region)
+	if cluster.Autopilot != nil && cluster.Autopilot.Enabled {
+		return monthly
+	}
+
+	// Node pools only know their initial size, so with autoscaling they are scaled to the current node count of the cluster
+	nodes := make([]float64, len(cluster.NodePools))
+	var initialNodes float64
+	for i, nodePool := range cluster.NodePools {
+		zones := len(nodePool.Locations)
+		if zones == 0 {
+			zones = 1
+		}
+		nodes[i] = float64(nodePool.InitialNodeCount) * float64(zones)
+		initialNodes += nodes[i]
+	}
+	scale := 1.0
+	if cluster.CurrentNodeCount > 0 && initialNodes > 0 {
+		scale = float64(cluster.CurrentNodeCount) / initialNodes
+	}
+	for i, nodePool := range cluster.NodePools {
+		if nodePool.Config == nil {
+			continue
+		}
+		node := machineCost(prices, nodePool.Config.MachineType, region, nodePool.Config.Preemptible)
+		node += prices.Disk(nodePool.Config.DiskType, nodePool.Config.DiskSizeGb, region)
+		monthly += node * nodes[i] * scale
+	}
+	return monthly
+}
+
+// groupCost - the instances of a managed instance group, priced from its template
+// Templates are fetched once per list, templates are shared by many groups
+func (b *ResourceBase) groupCost(ctx context.Context, serviceClient *compute.Service, templates map[string]*compute.InstanceTemplate, templateLink string, targetSize int64, region string) float64 {
+	if templateLink == "" || targetSize == 0 {
+		return 0
+	}
+	template, fetched := templates[templateLink]
+	if !fetched {
+		var err error
+		template, err = serviceClient.InstanceTemplates.Get(b.config.Project, templateLink[strings.LastIndex(templateLink, "/")+1:]).Context(ctx).Do()
+		if err != nil {
+			log.Printf("[Warning] Instance template %v left out of the cost estimate: %v", templateLink, err)
+		}
+		templates[templateLink] = template
+	}
+	if template == nil {
+		return 0
+	}
+	return float64(targetSize) * templateCost(b.prices(), template.Properties, region)
+}
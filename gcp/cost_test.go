@@ -0,0 +1,79 @@
+package gcp
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/arehmandev/gcp-nuke/pricing"
+	"github.com/arehmandev/gcp-nuke/report"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/container/v1"
+)
+
+func TestRemoveProjectCosts(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	zone := "zones/" + testZone
+	server.Add(zone+"/disks", &compute.Disk{Name: "scratch", Type: server.SelfLink(zone + "/diskTypes/pd-ssd"), SizeGb: 100})
+	server.Add(zone+"/instances", &compute.Instance{Name: "stopped", Status: "TERMINATED", MachineType: "n1-standard-4", Metadata: &compute.Metadata{}, Disks: []*compute.AttachedDisk{{DiskSizeGb: 10}}})
+	server.Add("global/instanceTemplates", &compute.InstanceTemplate{Name: "web", Properties: &compute.InstanceProperties{
+		MachineType: "e2-medium",
+		Disks:       []*compute.AttachedDisk{{InitializeParams: &compute.AttachedDiskInitializeParams{DiskType: "pd-standard", DiskSizeGb: 20}}},
+	}})
+	server.Add(zone+"/instanceGroupManagers", &compute.InstanceGroupManager{Name: "web", TargetSize: 3, InstanceTemplate: server.SelfLink("global/instanceTemplates/web")})
+	server.Add("locations/"+testRegion+"/clusters", &container.Cluster{
+		Name:             "gke",
+		Location:         testRegion,
+		CurrentNodeCount: 6,
+		NodePools: []*container.NodePool{{
+			Name:             "pool",
+			InitialNodeCount: 1,
+			Locations:        []string{testZone, "europe-west1-c", "europe-west1-d"},
+			Config:           &container.NodeConfig{MachineType: "e2-standard-2", DiskType: "pd-balanced", DiskSizeGb: 100},
+		}},
+	})
+	planFile := filepath.Join(t.TempDir(), "plan.json")
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.DryRun = true
+	testConfig.PlanFile = planFile
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := ioutil.ReadFile(planFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan := report.Report{}
+	if err := json.Unmarshal(content, &plan); err != nil {
+		t.Fatal(err)
+	}
+	prices := pricing.Default()
+	region := 1.1 // europe-west1
+	e2Standard2, _ := prices.Machine("e2-standard-2", testRegion, false)
+	expected := map[string]float64{
+		"ComputeDisks": 0.17 * 100 * region,
+		// Stopped instances only cost their disks
+		"ComputeInstances":          0.10 * 10 * region,
+		"ComputeInstanceGroupsZone": 3 * (0.033503*730 + 0.04*20) * region,
+		// Six nodes, twice the initial node count across three zones
+		"ContainerGKEClusters": 0.10*730*region + 6*(e2Standard2+0.10*100*region),
+	}
+	total := 0.0
+	for _, resource := range plan.Resources {
+		if math.Abs(resource.MonthlyCost-expected[resource.Type]) > 0.01 {
+			t.Errorf("expected %v to cost $%.2f/month, got: $%.2f", resource.Type, expected[resource.Type], resource.MonthlyCost)
+		}
+		total += expected[resource.Type]
+	}
+	if math.Abs(plan.MonthlyCost-total) > 0.01 {
+		t.Errorf("expected the plan to cost $%.2f/month, got: $%.2f", total, plan.MonthlyCost)
+	}
+	if cost := plan.Resource("ComputeDisks").Costs; math.Abs(cost["scratch"]-18.7) > 0.01 {
+		t.Errorf("expected the cost of each item in the plan, got: %v", plan.Resource("ComputeDisks"))
+	}
+}
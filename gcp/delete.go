@@ -1,106 +1,274 @@
 package gcp
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"log"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
+	"github.com/arehmandev/gcp-nuke/notify"
+	"github.com/arehmandev/gcp-nuke/pricing"
+	"github.com/arehmandev/gcp-nuke/report"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 )
 
 // RemoveProject  -
-func RemoveProject(config config.Config) {
-	helpers.SetupCloseHandler()
-	resourceMap := GetResourceMap(config)
+func RemoveProject(config config.Config, clients *Clients) error {
+	_, err := RemoveProjectReport(config, clients)
+	return err
+}
 
-	// Parallel deletion
-	errs, _ := errgroup.WithContext(config.Context)
+// RemoveProjectReport - RemoveProject, also returning the report of the run e.g. for the run history of gcp-nuke serve
+func RemoveProjectReport(config config.Config, clients *Clients) (runReport *report.Report, err error) {
+	// Root span of the run, every resource type and item span is a child of it
+	ctx, span := tracer().Start(config.Context, "RemoveProject", trace.WithAttributes(projectKey.String(config.Project), attribute.Bool("gcp_nuke.dry_run", config.DryRun)))
+	defer func() { endSpan(span, err) }()
+
+	// The report of the run is sent to notifications once the run completes or fails
+	notifiers, err := notify.New(config.Notifications)
+	if err != nil {
+		return nil, err
+	}
+	runReport = report.New(config.Project, config.DryRun)
+	defer func() {
+		runReport.Finish(err)
+		event := notify.EventCompleted
+		if err != nil {
+			event = notify.EventFailed
+		}
+		notify.Notify(notifiers, event, runReport)
+	}()
+	config.Context = withThrottle(report.NewContext(ctx, runReport), clients.throttle)
+
+	// The audit log is read on every run, so gcp-nuke serve picks up newer exports
+	if config.AuditLog != "" {
+		if config.Creators, err = loadAuditLog(config.AuditLog); err != nil {
+			return runReport, err
+		}
+		log.Printf("[Info] Read the creators of %v resources from the audit log %v", len(config.Creators), config.AuditLog)
+	}
+	if config.PriceTable != "" {
+		if config.Prices, err = pricing.Load(config.PriceTable); err != nil {
+			return runReport, err
+		}
+	}
+
+	resourceMap, err := GetResourceMap(config, clients)
+	if err != nil {
+		return runReport, err
+	}
+	for resourceName := range config.Resources {
+		if _, exists := resourceMap[resourceName]; !exists {
+			log.Printf("[Warning] Overrides set for unknown resource type %v", resourceName)
+		}
+	}
 
+	// Every resource type is listed before anything is deleted, so the whole plan is known up front
+	listErrs, _ := errgroup.WithContext(config.Context)
 	for _, resource := range resourceMap {
 		resource := resource
-		errs.Go(func() error {
+		listErrs.Go(func() error {
 			log.Println("[Info] Retrieving list of resources for", resource.Name())
-			resource.List(true)
-			if config.DryRun {
-				parallelDryRun(resourceMap, resource, config)
-				return nil
+			// Listing gets the timeout of the resource type, so a hung list call doesn't hold up the run
+			ctx, cancel := context.WithTimeout(config.Context, config.TimeoutFor(resource.Name()))
+			defer cancel()
+			items, err := resource.List(ctx, true)
+			if err != nil {
+				return fmt.Errorf("[Error] Resource: %v. Failed to list items. Details of error below:\n %v", resource.Name(), err.Error())
 			}
-			err := parallelResourceDeletion(resourceMap, resource, config)
+			resourcesListed.WithLabelValues(resource.Name()).Add(float64(len(items)))
+			runReport.SetItems(resource.Name(), items)
+			return nil
+		})
+	}
+	if err := listErrs.Wait(); err != nil {
+		return runReport, err
+	}
+	if config.PlanFile != "" {
+		if err := writePlan(config.PlanFile, runReport); err != nil {
+			return runReport, err
+		}
+	}
+
+	if config.DryRun {
+		for _, resourceName := range sortedResourceNames(resourceMap) {
+			parallelDryRun(resourceMap, resourceMap[resourceName], config)
+		}
+		dryRunOwners(runReport, config)
+		dryRunCost(runReport, config)
+		log.Printf("-- Deletion complete for project %v (dry-run: %v) --\n", config.Project, config.DryRun)
+		return runReport, nil
+	}
+	notify.Notify(notifiers, notify.EventPlan, runReport)
+
+	// Parallel deletion - one goroutine per resource type, these mostly wait on dependencies so only item deletions count towards --max-concurrency
+	errs, _ := errgroup.WithContext(config.Context)
+
+	// Closed once every item of the resource type is deleted, dependent types wait on it
+	// A resource type with nothing to delete is removed up front, so dependent types neither wait for it nor relist
+	removed := make(map[string]chan struct{})
+	for resourceName, resource := range resourceMap {
+		removed[resourceName] = make(chan struct{})
+		if len(resource.ToSlice()) == 0 {
+			close(removed[resourceName])
+		}
+	}
+	for _, resource := range resourceMap {
+		resource := resource
+		errs.Go(func() error {
+			ctx, span := tracer().Start(config.Context, resource.Name(), trace.WithAttributes(typeKey.String(resource.Name())))
+			err := parallelResourceDeletion(ctx, resourceMap, removed, resource, config)
+			endSpan(span, err)
+			runReport.SetResult(resource.Name(), resource.ToSlice(), err)
 
 			if err != nil {
 				return err
 			}
+			if !isClosed(removed[resource.Name()]) {
+				close(removed[resource.Name()])
+			}
 			return nil
 		})
 	}
 
 	// Wait for all deletions to complete, and check for errors
 	if err := errs.Wait(); err != nil {
-		log.Fatal(err)
+		return runReport, err
 	}
 
 	log.Printf("-- Deletion complete for project %v (dry-run: %v) --\n", config.Project, config.DryRun)
+	return runReport, nil
+}
+
+// writePlan - the report as json, before anything is deleted
+func writePlan(path string, runReport *report.Report) error {
+	content, err := json.MarshalIndent(runReport, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, content, 0644); err != nil {
+		return err
+	}
+	log.Println("[Info] Wrote the plan to", path)
+	return nil
+}
+
+// sortedResourceNames - resource type names in alphabetical order
+func sortedResourceNames(resourceMap map[string]Resource) []string {
+	names := []string{}
+	for resourceName := range resourceMap {
+		names = append(names, resourceName)
+	}
+	sort.Strings(names)
+	return names
 }
 
-func parallelResourceDeletion(resourceMap map[string]Resource, resource Resource, config config.Config) error {
+// parallelResourceDeletion - deletes the items of the resource type once every dependency is removed
+// Dependencies are waited on through their removed channel rather than their items, which are briefly empty whilst a dependency relists them
+func parallelResourceDeletion(ctx context.Context, resourceMap map[string]Resource, removed map[string]chan struct{}, resource Resource, config config.Config) error {
 	refreshCache := false
-	if len(resource.List(false)) == 0 {
+	if len(resource.ToSlice()) == 0 {
 		log.Println("[Skipping] No", resource.Name(), "items to delete")
 		return nil
 	}
 
-	timeOut := config.Timeout
-	pollTime := config.PollTime
-	seconds := 0
+	// The resource type deadline covers waiting on its dependencies, as well as its own deletion
+	deadline := resourceDeadline(resourceMap, resource, config)
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+	pollTime := config.PollTimeFor(resource.Name())
+	start := time.Now()
 
 	// Wait for dependencies to delete
 	for _, dependencyResourceName := range resource.Dependencies() {
-		if seconds > timeOut {
-			return fmt.Errorf("[Error] Resource %v timed out whilst waiting for dependency %v to delete. (%v seconds)", resource.Name(), dependencyResourceName, timeOut)
+		dependencyRemoved, exists := removed[dependencyResourceName]
+		if !exists || isClosed(dependencyRemoved) {
+			continue
 		}
-		dependencyResource := resourceMap[dependencyResourceName]
-		for len(dependencyResource.List(false)) != 0 {
-			refreshCache = true
-			time.Sleep(time.Duration(pollTime) * time.Second)
-			seconds += pollTime
-			log.Printf("[Waiting] Resource %v waiting for dependency %v to delete. (%v seconds)\n", resource.Name(), dependencyResource.Name(), seconds)
+		// A span per dependency waited on, so slow dependency chains stand out in a trace
+		_, waitSpan := tracer().Start(ctx, "Wait for "+dependencyResourceName, trace.WithAttributes(typeKey.String(resource.Name()), dependencyKey.String(dependencyResourceName)))
+		// Only relist once something was deleted, dependencies with nothing to delete are removed already
+		refreshCache = true
+		for !isClosed(dependencyRemoved) {
+			select {
+			case <-dependencyRemoved:
+			case <-ctx.Done():
+				err := fmt.Errorf("[Error] Resource %v timed out whilst waiting for dependency %v to delete. (%v)", resource.Name(), dependencyResourceName, deadline)
+				endSpan(waitSpan, err)
+				return err
+			case <-time.After(pollTime):
+				log.Printf("[Waiting] Resource %v waiting for dependency %v to delete. (%v)\n", resource.Name(), dependencyResourceName, time.Since(start).Round(time.Second))
+			}
 		}
+		endSpan(waitSpan, nil)
 	}
+	observeSince(dependencyWait, resource.Name(), start)
 
 	if refreshCache {
-		resource.List(refreshCache)
+		if _, err := resource.List(ctx, refreshCache); err != nil {
+			return err
+		}
 	}
 
-	log.Println("[Remove] Removing", resource.Name(), "items:", resource.List(false))
-	seconds = 0
-	err := resource.Remove()
+	log.Println("[Remove] Removing", resource.Name(), "items:", resource.ToSlice())
+	start = time.Now()
+	err := resource.Remove(ctx)
 
 	// Unfortunately the API seems inconsistent with timings, so retry until any dependent resources delete
 	for apiErrorCheck(err) {
-		resource.List(true)
-
-		if seconds > timeOut {
-			return fmt.Errorf("[Error] Resource %v timed out whilst trying to delete. (%v seconds). Details of error below:\n %v", resource.Name(), timeOut, err.Error())
+		if _, err := resource.List(ctx, true); err != nil {
+			return err
 		}
 
-		log.Printf("[Remove] In use Resource: %v. Items: %v. Waiting before retrying delete. (%v seconds)", resource.Name(), resource.List(false), seconds)
-		time.Sleep(time.Duration(pollTime) * time.Second)
-		seconds += pollTime
-		err = resource.Remove()
+		log.Printf("[Remove] In use Resource: %v. Items: %v. Waiting before retrying delete. (%v)", resource.Name(), resource.ToSlice(), time.Since(start).Round(time.Second))
+		if sleepErr := helpers.SleepContext(ctx, pollTime); sleepErr != nil {
+			return fmt.Errorf("[Error] Resource %v timed out whilst trying to delete. (%v). Details of error below:\n %v", resource.Name(), deadline, err.Error())
+		}
+		err = resource.Remove(ctx)
 	}
 
 	// Add some info to the error
 	if err != nil {
-		detailedError := fmt.Errorf("[Error] Resource: %v. Items: %v. Details of error below:\n %v", resource.Name(), resource.List(false), err.Error())
+		detailedError := fmt.Errorf("[Error] Resource: %v. Items: %v. Details of error below:\n %v", resource.Name(), resource.ToSlice(), err.Error())
 		err = detailedError
 	}
 
 	return err
 }
 
+// isClosed - whether the channel is closed, without blocking
+func isClosed(channel chan struct{}) bool {
+	select {
+	case <-channel:
+		return true
+	default:
+		return false
+	}
+}
+
+// resourceDeadline - the resource type timeout, plus the deadline of the slowest dependency it has to wait for
+func resourceDeadline(resourceMap map[string]Resource, resource Resource, config config.Config) time.Duration {
+	var slowestDependency time.Duration
+	for _, dependencyResourceName := range resource.Dependencies() {
+		dependencyResource, exists := resourceMap[dependencyResourceName]
+		if !exists {
+			continue
+		}
+		if dependencyDeadline := resourceDeadline(resourceMap, dependencyResource, config); dependencyDeadline > slowestDependency {
+			slowestDependency = dependencyDeadline
+		}
+	}
+	return config.TimeoutFor(resource.Name()) + slowestDependency
+}
+
 // apiErrorCheck - Not proud of this workaround for the inconsistent api timings, suggestions welcome
 func apiErrorCheck(err error) bool {
 	if err == nil {
@@ -0,0 +1,398 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/gcp/gcptest"
+	"github.com/arehmandev/gcp-nuke/notify"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/container/v1"
+)
+
+const (
+	testProject = "test-nuke-123456"
+	testZone    = "europe-west1-b"
+	testRegion  = "europe-west1"
+)
+
+func newTestServer() *gcptest.Server {
+	return gcptest.NewServer(testProject, []string{testZone}, []string{testRegion})
+}
+
+// newTestConfig - config pointing every api at the fake server, with short timeouts and polltimes
+func newTestConfig(t *testing.T, server *gcptest.Server) (config.Config, *Clients) {
+	testConfig := config.Config{
+		Project:               testProject,
+		Context:               context.Background(),
+		Timeout:               5 * time.Second,
+		PollTime:              10 * time.Millisecond,
+		MaxConcurrency:        4,
+		Endpoint:              server.URL,
+		WithoutAuthentication: true,
+	}
+	clients := NewClients(testConfig)
+
+	var err error
+	if testConfig.Zones, err = GetZones(clients, testProject); err != nil {
+		t.Fatal(err)
+	}
+	if testConfig.Regions, err = GetRegions(clients, testProject); err != nil {
+		t.Fatal(err)
+	}
+	return testConfig, clients
+}
+
+// seedProject - a small project with a vm, a managed instance group, a gke cluster and the network they sit in
+func seedProject(server *gcptest.Server) {
+	zone := "zones/" + testZone
+	region := "regions/" + testRegion
+	network := server.SelfLink("global/networks/vpc")
+	subnetwork := server.SelfLink(region + "/subnetworks/subnet")
+
+	server.Add("global/networks", &compute.Network{
+		Name:     "vpc",
+		Peerings: []*compute.NetworkPeering{{Name: "peer", Network: "https://www.googleapis.com/compute/v1/projects/other/global/networks/other"}},
+	})
+	server.Add(region+"/subnetworks", &compute.Subnetwork{Name: "subnet", Network: network})
+	server.Add("global/firewalls", &compute.Firewall{Name: "allow-ssh", Network: network})
+	server.Add(region+"/routers", &compute.Router{Name: "router", Network: network})
+
+	server.Add(zone+"/disks", &compute.Disk{Name: "boot", Users: []string{server.SelfLink(zone + "/instances/vm")}})
+	server.Add(zone+"/disks", &compute.Disk{Name: "scratch"})
+	server.Add(zone+"/instances", &compute.Instance{
+		Name:              "vm",
+		Metadata:          &compute.Metadata{},
+		Disks:             []*compute.AttachedDisk{{DeviceName: "boot", Source: server.SelfLink(zone + "/disks/boot")}},
+		NetworkInterfaces: []*compute.NetworkInterface{{Network: network, Subnetwork: subnetwork}},
+	})
+
+	server.Add("global/instanceTemplates", &compute.InstanceTemplate{Name: "web"})
+	server.Add(zone+"/instanceGroupManagers", &compute.InstanceGroupManager{Name: "web", InstanceTemplate: server.SelfLink("global/instanceTemplates/web")})
+	server.Add(zone+"/autoscalers", &compute.Autoscaler{Name: "web", Target: server.SelfLink(zone + "/instanceGroupManagers/web")})
+
+	server.Add(zone+"/instanceGroupManagers", &compute.InstanceGroupManager{Name: "gke-pool"})
+	server.Add("locations/"+testRegion+"/clusters", &container.Cluster{
+		Name:      "gke",
+		Location:  testRegion,
+		NodePools: []*container.NodePool{{Name: "pool", InstanceGroupUrls: []string{server.SelfLink(zone + "/instanceGroupManagers/gke-pool")}}},
+	})
+}
+
+func TestRemoveProject(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	seedProject(server)
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if paths := server.Paths(); len(paths) != 0 {
+		t.Errorf("expected every resource to be deleted, remaining: %v", paths)
+	}
+	if !containsRequest(server, "POST", "/instances/vm/setDiskAutoDelete") {
+		t.Error("expected the attached boot disk to be set to auto delete")
+	}
+	if containsRequest(server, "DELETE", "/instanceGroupManagers/gke-pool") {
+		t.Error("expected the gke node pool instance group to be left to the cluster deletion")
+	}
+}
+
+func TestRemoveProjectDryRun(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	seedProject(server)
+	before := server.Paths()
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.DryRun = true
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if after := server.Paths(); len(after) != len(before) {
+		t.Errorf("expected nothing to be deleted in a dry run, before: %v after: %v", before, after)
+	}
+	if containsRequest(server, "DELETE", "") || containsRequest(server, "POST", "") {
+		t.Errorf("expected no mutating requests in a dry run: %v", server.Requests())
+	}
+}
+
+func TestRemoveProjectRetriesResourceInUse(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Add("global/firewalls", &compute.Firewall{Name: "allow-ssh"})
+	server.InUse("global/firewalls/allow-ssh", 3)
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if server.Exists("global/firewalls/allow-ssh") {
+		t.Error("expected the firewall to be deleted once no longer in use")
+	}
+}
+
+func TestRemoveProjectGhostInstanceGroups(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	path := "zones/" + testZone + "/instanceGroupManagers/ghost"
+	server.Add("zones/"+testZone+"/instanceGroupManagers", &compute.InstanceGroupManager{Name: "ghost"})
+	server.Ghost(path, 3)
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if server.Exists(path) {
+		t.Error("expected the ghost instance group to stop being listed")
+	}
+}
+
+func TestRemoveProjectTimesOut(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Add("zones/"+testZone+"/disks", &compute.Disk{Name: "slow"})
+	server.SlowOperation("zones/"+testZone+"/disks/slow", 1000)
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.Timeout = 200 * time.Millisecond
+
+	err := RemoveProject(testConfig, clients)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Fatalf("expected the slow disk deletion to time out, got: %v", err)
+	}
+}
+
+// TestRemoveProjectListTimesOut - a hung list call is cancelled after the timeout of its resource type
+func TestRemoveProjectListTimesOut(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Hang("/compute/v1/projects/" + testProject + "/zones/" + testZone + "/disks")
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.Timeout = time.Hour
+	testConfig.Resources = map[string]config.ResourceConfig{"ComputeDisks": {Timeout: 200 * time.Millisecond}}
+
+	err := RemoveProject(testConfig, clients)
+	if err == nil || !strings.Contains(err.Error(), "ComputeDisks. Failed to list items") {
+		t.Fatalf("expected the hung disk list to time out, got: %v", err)
+	}
+}
+
+func TestRemoveProjectPaginates(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.PageSize = 2
+	for _, name := range []string{"disk-1", "disk-2", "disk-3", "disk-4", "disk-5"} {
+		server.Add("zones/"+testZone+"/disks", &compute.Disk{Name: name})
+	}
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if paths := server.Paths(); len(paths) != 0 {
+		t.Errorf("expected disks on every page to be deleted, remaining: %v", paths)
+	}
+}
+
+func TestRemoveProjectListFails(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	seedProject(server)
+	testConfig, clients := newTestConfig(t, server)
+	server.Fail("/compute/v1/projects/"+testProject+"/zones/"+testZone+"/disks", http.StatusForbidden, "forbidden", "Required 'compute.disks.list' permission")
+
+	err := RemoveProject(testConfig, clients)
+	if err == nil || !strings.Contains(err.Error(), "ComputeDisks") {
+		t.Fatalf("expected the failed disk list to fail the run, got: %v", err)
+	}
+	if containsRequest(server, "DELETE", "") {
+		t.Error("expected nothing to be deleted once a list fails")
+	}
+}
+
+// TestThrottlePerRun - concurrent runs of gcp-nuke serve each keep the rate limit and deletion slots of their own config
+func TestThrottlePerRun(t *testing.T) {
+	slow := NewClients(config.Config{RateLimit: 1, MaxConcurrency: 1})
+	fast := NewClients(config.Config{RateLimit: 50})
+
+	if limit := slow.throttle.apiLimiter("compute").Limit(); limit != 1 {
+		t.Errorf("expected the rate limit of the first run to be kept, got: %v", limit)
+	}
+	if limit := fast.throttle.apiLimiter("compute").Limit(); limit != 50 {
+		t.Errorf("expected the rate limit of the second run, got: %v", limit)
+	}
+	slots := deletionSlots(withThrottle(context.Background(), slow.throttle))
+	if !slots.TryAcquire(1) || slots.TryAcquire(1) {
+		t.Error("expected the first run to keep a single deletion slot")
+	}
+	if deletionSlots(withThrottle(context.Background(), fast.throttle)) == slots {
+		t.Error("expected each run to have its own deletion slots")
+	}
+}
+
+func TestRemoveProjectNotifies(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	seedProject(server)
+	events := []string{}
+	diskExistsAtPlan := false
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := struct {
+			Event string
+			Text  string
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		if payload.Event == notify.EventPlan {
+			diskExistsAtPlan = server.Exists("zones/" + testZone + "/disks/scratch")
+		}
+		events = append(events, payload.Event+": "+payload.Text)
+	}))
+	defer webhook.Close()
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.Notifications = []config.Notification{{URL: webhook.URL, Events: []string{notify.EventPlan, notify.EventCompleted, notify.EventFailed}}}
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 2 || !strings.HasPrefix(events[0], "plan: ") || !strings.HasPrefix(events[1], "completed: ") {
+		t.Fatalf("expected the plan then completed events, got: %v", events)
+	}
+	if !diskExistsAtPlan {
+		t.Error("expected the plan to be sent before anything was deleted")
+	}
+	if !strings.Contains(events[1], "ComputeDisks: 1 of 1 deleted") {
+		t.Errorf("expected deletion counts in the summary, got: %v", events[1])
+	}
+}
+
+func TestRemoveProjectNotifiesFailure(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Add("zones/"+testZone+"/disks", &compute.Disk{Name: "slow"})
+	server.SlowOperation("zones/"+testZone+"/disks/slow", 1000)
+	events := []string{}
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		events = append(events, string(body))
+	}))
+	defer webhook.Close()
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.Timeout = 200 * time.Millisecond
+	testConfig.Notifications = []config.Notification{{URL: webhook.URL, Format: notify.FormatSlack}}
+
+	if err := RemoveProject(testConfig, clients); err == nil {
+		t.Fatal("expected the slow disk deletion to time out")
+	}
+
+	if len(events) != 1 || !strings.Contains(events[0], "failed for project "+testProject) || !strings.Contains(events[0], "remaining: slow") {
+		t.Errorf("expected a single failed notification, got: %v", events)
+	}
+}
+
+// TestRemoveProjectNotifiesListFailure - a run aborted by a failed list still sends the failed notification
+func TestRemoveProjectNotifiesListFailure(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	seedProject(server)
+	events := []string{}
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := struct {
+			Event string
+			Text  string
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Error(err)
+		}
+		events = append(events, payload.Event+": "+payload.Text)
+	}))
+	defer webhook.Close()
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.Notifications = []config.Notification{{URL: webhook.URL, Events: []string{notify.EventPlan, notify.EventCompleted, notify.EventFailed}}}
+	server.Fail("/compute/v1/projects/"+testProject+"/zones/"+testZone+"/disks", http.StatusForbidden, "forbidden", "Required 'compute.disks.list' permission")
+
+	if err := RemoveProject(testConfig, clients); err == nil {
+		t.Fatal("expected the failed disk list to fail the run")
+	}
+
+	if len(events) != 1 || !strings.HasPrefix(events[0], notify.EventFailed+": ") || !strings.Contains(events[0], "compute.disks.list") {
+		t.Errorf("expected a single failed notification with the list error, got: %v", events)
+	}
+}
+
+func containsRequest(server *gcptest.Server, method, pathSuffix string) bool {
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request, method+" ") && strings.HasSuffix(request, pathSuffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteOrder - the changes made on the fake server in order, as "METHOD path" with the path relative to the project, reads are left out
+func deleteOrder(server *gcptest.Server) []string {
+	order := []string{}
+	for _, request := range server.Requests() {
+		method := request[:strings.Index(request, " ")]
+		if method == http.MethodGet {
+			continue
+		}
+		path := request[len(method)+1:]
+		if i := strings.Index(path, "/projects/"+testProject+"/"); i >= 0 {
+			path = path[i+len("/projects/"+testProject+"/"):]
+		}
+		order = append(order, method+" "+path)
+	}
+	return order
+}
+
+// assertDeletedBefore - fails unless both changes were made, before first
+func assertDeletedBefore(t *testing.T, order []string, before, after string) {
+	t.Helper()
+	first, second := -1, -1
+	for i, change := range order {
+		if change == before && first < 0 {
+			first = i
+		}
+		if change == after && second < 0 {
+			second = i
+		}
+	}
+	switch {
+	case first < 0 || second < 0:
+		t.Errorf("expected %v and %v, got: %v", before, after, order)
+	case first > second:
+		t.Errorf("expected %v before %v, got: %v", before, after, order)
+	}
+}
+
+// assertDeletedOnce - fails when a delete had to be retried
+func assertDeletedOnce(t *testing.T, order []string) {
+	t.Helper()
+	deletes := map[string]int{}
+	for _, change := range order {
+		if strings.HasPrefix(change, http.MethodDelete+" ") {
+			deletes[change]++
+		}
+	}
+	for change, count := range deletes {
+		if count != 1 {
+			t.Errorf("expected %v once, without retries, got %v deletes", change, count)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/dns/v1"
+)
+
+// recordSetDeletionsPerChange - record sets deleted per change, the default rrsetDeletionsPerChange quota
+const recordSetDeletionsPerChange = 1000
+
+// DNSManagedZones - public and private Cloud DNS zones, emptied of their record sets before they are deleted
+// Private zones are detached from their networks first, so the networks can go once the zones are gone
+type DNSManagedZones struct {
+	serviceClient *dns.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+// dnsManagedZoneProperties -
+type dnsManagedZoneProperties struct {
+	dnsName string
+	private bool
+}
+
+func init() {
+	register(func() Resource {
+		return &DNSManagedZones{}
+	})
+}
+
+// Name - Name of the resourceLister for DNSManagedZones
+func (c *DNSManagedZones) Name() string {
+	return "DNSManagedZones"
+}
+
+// ToSlice - Name of the resourceLister for DNSManagedZones
+func (c *DNSManagedZones) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *DNSManagedZones) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.DNS()
+	return err
+}
+
+// List - Returns a list of all DNSManagedZones
+func (c *DNSManagedZones) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	zoneListCall := c.serviceClient.ManagedZones.List(c.base.config.Project)
+	err := zoneListCall.Pages(ctx, func(zoneList *dns.ManagedZonesListResponse) error {
+		for _, zone := range zoneList.ManagedZones {
+			if c.base.skip(c.Name(), listedItem{name: zone.Name, labels: zone.Labels, created: zone.CreationTime}) {
+				continue
+			}
+			c.resourceMap.Store(zone.Name, dnsManagedZoneProperties{
+				dnsName: zone.DnsName,
+				private: zone.Visibility == "private",
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *DNSManagedZones) Dependencies() []string {
+	// GKE clusters using Cloud DNS delete their own zones
+	a := ContainerGKEClusters{}
+	return []string{a.Name()}
+}
+
+// Remove - deletes every record set but the SOA and NS records of the zone apex, detaches private zones from their networks, then deletes the zone
+func (c *DNSManagedZones) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		zoneName := key.(string)
+		zoneResource := value.(dnsManagedZoneProperties)
+
+		// Parallel zone deletion
+		errs.Go(zoneName, "global", func(ctx context.Context) error {
+			description := fmt.Sprintf("%v [type: %v project: %v]", zoneName, c.Name(), c.base.config.Project)
+			if err := c.removeRecordSets(ctx, zoneName, zoneResource.dnsName, description); err != nil {
+				return err
+			}
+			if zoneResource.private {
+				if err := c.detachNetworks(ctx, zoneName, description); err != nil {
+					return err
+				}
+			}
+
+			if err := c.serviceClient.ManagedZones.Delete(c.base.config.Project, zoneName).Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v", description)
+			c.resourceMap.Delete(zoneName)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
+
+// removeRecordSets - deletes the record sets of the zone in changes of up to recordSetDeletionsPerChange, waiting on each change
+// The SOA and NS records of the zone apex can't be deleted and go with the zone, NS records delegating subdomains are deleted
+func (c *DNSManagedZones) removeRecordSets(ctx context.Context, zoneName, dnsName, description string) error {
+	recordSets := []*dns.ResourceRecordSet{}
+	recordSetListCall := c.serviceClient.ResourceRecordSets.List(c.base.config.Project, zoneName)
+	err := recordSetListCall.Pages(ctx, func(recordSetList *dns.ResourceRecordSetsListResponse) error {
+		for _, recordSet := range recordSetList.Rrsets {
+			if recordSet.Name == dnsName && (recordSet.Type == "SOA" || recordSet.Type == "NS") {
+				continue
+			}
+			recordSets = append(recordSets, recordSet)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for start := 0; start < len(recordSets); start += recordSetDeletionsPerChange {
+		end := start + recordSetDeletionsPerChange
+		if end > len(recordSets) {
+			end = len(recordSets)
+		}
+		change, err := c.serviceClient.Changes.Create(c.base.config.Project, zoneName, &dns.Change{Deletions: recordSets[start:end]}).Context(ctx).Do()
+		if err != nil {
+			return fmt.Errorf("deleting the record sets of zone %v: %v", zoneName, err)
+		}
+		recordSetsDescription := fmt.Sprintf("%v record sets of %v", end-start, description)
+		err = c.base.waitFor(ctx, c.Name(), "deleted", recordSetsDescription, func() (string, error) {
+			checkChange, err := c.serviceClient.Changes.Get(c.base.config.Project, zoneName, change.Id).Context(ctx).Do()
+			if err != nil {
+				return "", err
+			}
+			return strings.ToUpper(checkChange.Status), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// detachNetworks - removes the networks and GKE clusters the private zone is visible to
+func (c *DNSManagedZones) detachNetworks(ctx context.Context, zoneName, description string) error {
+	patch := &dns.ManagedZone{
+		PrivateVisibilityConfig: &dns.ManagedZonePrivateVisibilityConfig{
+			Networks:        []*dns.ManagedZonePrivateVisibilityConfigNetwork{},
+			GkeClusters:     []*dns.ManagedZonePrivateVisibilityConfigGKECluster{},
+			ForceSendFields: []string{"Networks", "GkeClusters"},
+		},
+	}
+	operation, err := c.serviceClient.ManagedZones.Patch(c.base.config.Project, zoneName, patch).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("detaching zone %v from its networks: %v", zoneName, err)
+	}
+	return c.base.waitFor(ctx, c.Name(), "detached", description, func() (string, error) {
+		checkOpp, err := c.serviceClient.ManagedZoneOperations.Get(c.base.config.Project, zoneName, operation.Id).Context(ctx).Do()
+		if err != nil {
+			return "", err
+		}
+		return strings.ToUpper(checkOpp.Status), nil
+	})
+}
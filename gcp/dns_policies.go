@@ -0,0 +1,176 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/dns/v1"
+)
+
+// responsePolicyPrefix - resource map key prefix of response policies, server policies are keyed by name alone
+const responsePolicyPrefix = "responsePolicies/"
+
+// DNSPolicies - Cloud DNS server policies and response policies, detached from their networks before they are deleted
+// A network can't be deleted whilst a policy is still attached to it
+type DNSPolicies struct {
+	serviceClient *dns.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &DNSPolicies{}
+	})
+}
+
+// Name - Name of the resourceLister for DNSPolicies
+func (c *DNSPolicies) Name() string {
+	return "DNSPolicies"
+}
+
+// ToSlice - Name of the resourceLister for DNSPolicies
+func (c *DNSPolicies) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *DNSPolicies) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.DNS()
+	return err
+}
+
+// List - Returns a list of all DNSPolicies, server policies by name and response policies by responsePolicies/name
+func (c *DNSPolicies) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	policyListCall := c.serviceClient.Policies.List(c.base.config.Project)
+	err := policyListCall.Pages(ctx, func(policyList *dns.PoliciesListResponse) error {
+		for _, policy := range policyList.Policies {
+			if c.base.skip(c.Name(), listedItem{name: policy.Name}) {
+				continue
+			}
+			c.resourceMap.Store(policy.Name, len(policy.Networks))
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	responsePolicyListCall := c.serviceClient.ResponsePolicies.List(c.base.config.Project)
+	err = responsePolicyListCall.Pages(ctx, func(responsePolicyList *dns.ResponsePoliciesListResponse) error {
+		for _, responsePolicy := range responsePolicyList.ResponsePolicies {
+			key := responsePolicyPrefix + responsePolicy.ResponsePolicyName
+			if c.base.skip(c.Name(), listedItem{name: key, labels: responsePolicy.Labels}) {
+				continue
+			}
+			c.resourceMap.Store(key, len(responsePolicy.Networks)+len(responsePolicy.GkeClusters))
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *DNSPolicies) Dependencies() []string {
+	return []string{}
+}
+
+// Remove - detaches each policy from its networks and deletes it, response policies are emptied of their rules first
+func (c *DNSPolicies) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		policyID := key.(string)
+		attachments := value.(int)
+
+		// Parallel policy deletion
+		errs.Go(policyID, "global", func(ctx context.Context) error {
+			var err error
+			if strings.HasPrefix(policyID, responsePolicyPrefix) {
+				err = c.removeResponsePolicy(ctx, strings.TrimPrefix(policyID, responsePolicyPrefix), attachments)
+			} else {
+				err = c.removePolicy(ctx, policyID, attachments)
+			}
+			if err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v]", policyID, c.Name(), c.base.config.Project)
+			c.resourceMap.Delete(policyID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
+
+// removePolicy - detaches the server policy from its networks, then deletes it
+func (c *DNSPolicies) removePolicy(ctx context.Context, policyName string, attachments int) error {
+	if attachments > 0 {
+		patch := &dns.Policy{
+			Networks:        []*dns.PolicyNetwork{},
+			ForceSendFields: []string{"Networks"},
+		}
+		if _, err := c.serviceClient.Policies.Patch(c.base.config.Project, policyName, patch).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("detaching policy %v from its networks: %v", policyName, err)
+		}
+	}
+	return c.serviceClient.Policies.Delete(c.base.config.Project, policyName).Context(ctx).Do()
+}
+
+// removeResponsePolicy - deletes the rules of the response policy and detaches it from its networks and GKE clusters, then deletes it
+func (c *DNSPolicies) removeResponsePolicy(ctx context.Context, responsePolicyName string, attachments int) error {
+	rules := []string{}
+	ruleListCall := c.serviceClient.ResponsePolicyRules.List(c.base.config.Project, responsePolicyName)
+	err := ruleListCall.Pages(ctx, func(ruleList *dns.ResponsePolicyRulesListResponse) error {
+		for _, rule := range ruleList.ResponsePolicyRules {
+			rules = append(rules, rule.RuleName)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if err := c.serviceClient.ResponsePolicyRules.Delete(c.base.config.Project, responsePolicyName, rule).Context(ctx).Do(); err != nil && !isNotFound(err) {
+			return fmt.Errorf("deleting rule %v of response policy %v: %v", rule, responsePolicyName, err)
+		}
+	}
+
+	if attachments > 0 {
+		patch := &dns.ResponsePolicy{
+			Networks:        []*dns.ResponsePolicyNetwork{},
+			GkeClusters:     []*dns.ResponsePolicyGKECluster{},
+			ForceSendFields: []string{"Networks", "GkeClusters"},
+		}
+		if _, err := c.serviceClient.ResponsePolicies.Patch(c.base.config.Project, responsePolicyName, patch).Context(ctx).Do(); err != nil {
+			return fmt.Errorf("detaching response policy %v from its networks: %v", responsePolicyName, err)
+		}
+	}
+	return c.serviceClient.ResponsePolicies.Delete(c.base.config.Project, responsePolicyName).Context(ctx).Do()
+}
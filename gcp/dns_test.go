@@ -0,0 +1,61 @@
+package gcp
+
+import (
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/dns/v1"
+)
+
+// TestRemoveProjectDNS - zones are emptied and policies detached, so the network they're attached to can be deleted after them
+func TestRemoveProjectDNS(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	network := server.SelfLink("global/networks/vpc")
+
+	server.Add("global/networks", &compute.Network{Name: "vpc"})
+	server.Add("managedZones", &dns.ManagedZone{Name: "public", DnsName: "example.com.", Visibility: "public"})
+	server.AddRecordSet("public", &dns.ResourceRecordSet{Name: "example.com.", Type: "SOA"})
+	server.AddRecordSet("public", &dns.ResourceRecordSet{Name: "example.com.", Type: "NS"})
+	server.AddRecordSet("public", &dns.ResourceRecordSet{Name: "example.com.", Type: "TXT"})
+	server.AddRecordSet("public", &dns.ResourceRecordSet{Name: "www.example.com.", Type: "A"})
+	server.AddRecordSet("public", &dns.ResourceRecordSet{Name: "dev.example.com.", Type: "NS"})
+	server.Add("managedZones", &dns.ManagedZone{
+		Name:                    "internal",
+		DnsName:                 "internal.",
+		Visibility:              "private",
+		PrivateVisibilityConfig: &dns.ManagedZonePrivateVisibilityConfig{Networks: []*dns.ManagedZonePrivateVisibilityConfigNetwork{{NetworkUrl: network}}},
+	})
+	server.AddRecordSet("internal", &dns.ResourceRecordSet{Name: "internal.", Type: "SOA"})
+	server.AddRecordSet("internal", &dns.ResourceRecordSet{Name: "db.internal.", Type: "A"})
+	server.Add("policies", &dns.Policy{Name: "inbound", EnableInboundForwarding: true, Networks: []*dns.PolicyNetwork{{NetworkUrl: network}}})
+	server.Add("responsePolicies", &dns.ResponsePolicy{ResponsePolicyName: "blocklist", Networks: []*dns.ResponsePolicyNetwork{{NetworkUrl: network}}})
+	server.Add("responsePolicies/blocklist/rules", &dns.ResponsePolicyRule{RuleName: "ads", DnsName: "ads.example.net."})
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := server.Paths(); len(remaining) != 0 {
+		t.Errorf("expected the zones, their record sets, the policies and the network to be deleted, remaining: %v", remaining)
+	}
+	order := deleteOrder(server)
+	assertDeletedOnce(t, order)
+	for _, before := range [][]string{
+		{"POST managedZones/public/changes", "DELETE managedZones/public"},
+		{"POST managedZones/internal/changes", "PATCH managedZones/internal"},
+		{"PATCH managedZones/internal", "DELETE managedZones/internal"},
+		{"PATCH policies/inbound", "DELETE policies/inbound"},
+		{"DELETE responsePolicies/blocklist/rules/ads", "DELETE responsePolicies/blocklist"},
+		{"PATCH responsePolicies/blocklist", "DELETE responsePolicies/blocklist"},
+		{"DELETE managedZones/internal", "DELETE global/networks/vpc"},
+		{"DELETE policies/inbound", "DELETE global/networks/vpc"},
+		{"DELETE responsePolicies/blocklist", "DELETE global/networks/vpc"},
+	} {
+		assertDeletedBefore(t, order, before[0], before[1])
+	}
+	if containsRequest(server, "PATCH", "/managedZones/public") {
+		t.Error("expected the public zone to be deleted without a patch")
+	}
+}
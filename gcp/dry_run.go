@@ -1,16 +1,53 @@
 package gcp
 
 import (
+	"fmt"
 	"log"
 
 	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/report"
 )
 
 func parallelDryRun(resourceMap map[string]Resource, resource Resource, config config.Config) {
-	resourceList := resource.List(false)
+	resourceList := resource.ToSlice()
 	if len(resourceList) == 0 {
 		log.Printf("[Dryrun] [Skip] Resource type %v has nothing to destroy [project: %v]", resource.Name(), config.Project)
 		return
 	}
-	log.Printf("[Dryrun] Resource type %v with resources %v would be destroyed [project: %v]", resource.Name(), resourceList, config.Project)
+	resourceReport := report.FromContext(config.Context).Resource(resource.Name())
+	if resourceReport == nil || resourceReport.MonthlyCost == 0 {
+		log.Printf("[Dryrun] Resource type %v with resources %v would be destroyed [project: %v]", resource.Name(), resourceList, config.Project)
+		return
+	}
+	items := []string{}
+	for _, item := range resourceList {
+		if cost := resourceReport.Costs[item]; cost > 0 {
+			item = fmt.Sprintf("%v ($%.2f/month)", item, cost)
+		}
+		items = append(items, item)
+	}
+	log.Printf("[Dryrun] Resource type %v with resources %v would be destroyed, saving an estimated $%.2f/month [project: %v]", resource.Name(), items, resourceReport.MonthlyCost, config.Project)
+}
+
+// dryRunCost - the estimated monthly cost of everything the run would delete
+func dryRunCost(runReport *report.Report, config config.Config) {
+	if runReport.MonthlyCost > 0 {
+		log.Printf("[Dryrun] Deleting these resources would save an estimated $%.2f/month [project: %v]", runReport.MonthlyCost, config.Project)
+	}
+}
+
+// dryRunOwners - the plan grouped by owner, unless nothing has an owner
+func dryRunOwners(runReport *report.Report, config config.Config) {
+	byOwner := runReport.ByOwner()
+	if len(byOwner) == 0 || (len(byOwner) == 1 && byOwner[0].Owner == report.Unowned) {
+		return
+	}
+	for _, owner := range byOwner {
+		for _, resource := range owner.Resources {
+			log.Printf("[Dryrun] Owner %v: resource type %v with resources %v would be destroyed [project: %v]", owner.Owner, resource.Type, resource.Items, config.Project)
+		}
+		if owner.MonthlyCost > 0 {
+			log.Printf("[Dryrun] Owner %v: an estimated $%.2f/month [project: %v]", owner.Owner, owner.MonthlyCost, config.Project)
+		}
+	}
 }
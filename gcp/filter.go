@@ -0,0 +1,147 @@
+package gcp
+
+import (
+	"log"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"github.com/arehmandev/gcp-nuke/report"
+)
+
+// now - current time, replaced in tests
+var now = time.Now
+
+// defaultOwnerLabels - labels holding the owner of an item when --owner-label isn't set
+var defaultOwnerLabels = []string{"owner", "team"}
+
+// listedItem - what a lister knows about an item, for the ttl and owner filters
+type listedItem struct {
+	// name - key of the item in the resource map
+	name   string
+	labels map[string]string
+	// created - creation timestamp, RFC3339
+	created string
+	// createdBy - the created-by metadata of instances, the creator of Cloud Run services and jobs, or the owner of BigQuery transfer configs
+	createdBy string
+	// monthlyCost - estimated monthly cost in USD, see cost.go
+	monthlyCost float64
+	// email - email of service accounts and their keys, their name is the account id of the email
+	email string
+}
+
+// skip - whether a listed item is left alone by the run, called by every lister before storing an item
+//
+// With --ttl-label only expired items are deleted: the first ttl label found on the item is either a date
+// the item expires at e.g. expires-at=2026-10-20, or a lifetime counted from its creation e.g. ttl=48h or ttl=7d.
+// Items without a ttl label expire after --ttl-default, or are never deleted when it isn't set.
+// With --owner only items of those owners are deleted, and with --name-regex only items with a matching name.
+// With --email-regex only service accounts and keys with a matching email are deleted, other items aren't affected.
+// The owner and cost of every item kept is recorded in the report for the plan.
+func (b *ResourceBase) skip(resourceName string, item listedItem) bool {
+	if len(b.config.NamePatterns) > 0 && !b.nameMatches(item) {
+		return true
+	}
+	if len(b.config.EmailPatterns) > 0 && item.email != "" && !b.emailMatches(item.email) {
+		return true
+	}
+	if len(b.config.TTLLabels) > 0 {
+		expiry, ok := b.expiry(resourceName, item)
+		if !ok || now().Before(expiry) {
+			return true
+		}
+	}
+	owner := b.owner(resourceName, item)
+	if len(b.config.Owners) > 0 && !helpers.SliceContains(b.config.Owners, owner) {
+		return true
+	}
+	if runReport := report.FromContext(b.config.Context); runReport != nil {
+		runReport.SetOwner(resourceName, item.name, owner)
+		if item.monthlyCost > 0 {
+			runReport.SetCost(resourceName, item.name, item.monthlyCost)
+		}
+	}
+	return false
+}
+
+// needsCreationTime - whether skip reads the creation time of items, for listers which have to fetch it item by item
+func (b *ResourceBase) needsCreationTime() bool {
+	return len(b.config.TTLLabels) > 0
+}
+
+// nameMatches - whether the name of the item, without any region or collection in its key, matches one of the --name-regex patterns
+func (b *ResourceBase) nameMatches(item listedItem) bool {
+	name := keyName(item.name)
+	if item.email != "" {
+		name = serviceAccountID(item.email)
+	}
+	for _, namePattern := range b.config.NamePatterns {
+		if namePattern.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// emailMatches - whether the email of a service account matches one of the --email-regex patterns
+func (b *ResourceBase) emailMatches(email string) bool {
+	for _, emailPattern := range b.config.EmailPatterns {
+		if emailPattern.MatchString(email) {
+			return true
+		}
+	}
+	return false
+}
+
+// owner - the first owner label of the item, its created-by metadata or its creator in the audit log, in that order
+func (b *ResourceBase) owner(resourceName string, item listedItem) string {
+	labels := b.config.OwnerLabels
+	if len(labels) == 0 {
+		labels = defaultOwnerLabels
+	}
+	for _, label := range labels {
+		if value := item.labels[label]; value != "" {
+			return value
+		}
+	}
+	if item.createdBy != "" {
+		return item.createdBy
+	}
+	if creator := creatorOf(b.config.Creators, resourceName, item.name); creator != "" {
+		return creator
+	}
+	return report.Unowned
+}
+
+// expiry - when the item expires, ok is false when it has no usable ttl
+func (b *ResourceBase) expiry(resourceName string, item listedItem) (expiry time.Time, ok bool) {
+	for _, label := range b.config.TTLLabels {
+		value, exists := item.labels[label]
+		if !exists {
+			continue
+		}
+		if date, err := time.Parse("2006-01-02", value); err == nil {
+			return date, true
+		}
+		ttl, err := config.ParseTTL(value)
+		if err != nil {
+			log.Printf("[Warning] %v %v: ignoring label %v=%v, expected a date such as 2026-10-20 or a duration such as 48h or 7d", resourceName, item.name, label, value)
+			return time.Time{}, false
+		}
+		return expiresAfter(resourceName, item, ttl)
+	}
+	if b.config.TTLDefault == 0 {
+		return time.Time{}, false
+	}
+	return expiresAfter(resourceName, item, b.config.TTLDefault)
+}
+
+// expiresAfter - the creation timestamp plus the ttl
+func expiresAfter(resourceName string, item listedItem, ttl time.Duration) (expiry time.Time, ok bool) {
+	creation, err := time.Parse(time.RFC3339, item.created)
+	if err != nil {
+		log.Printf("[Warning] %v %v: ignoring the ttl, no valid creation timestamp %q", resourceName, item.name, item.created)
+		return time.Time{}, false
+	}
+	return creation.Add(ttl), true
+}
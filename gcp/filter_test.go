@@ -0,0 +1,136 @@
+package gcp
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/report"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/container/v1"
+)
+
+func TestRemoveProjectTTL(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+
+	server := newTestServer()
+	defer server.Close()
+	zone := "zones/" + testZone
+	disks := []*compute.Disk{
+		{Name: "expired-date", Labels: map[string]string{"expires-at": "2026-10-01"}},
+		{Name: "future-date", Labels: map[string]string{"expires-at": "2026-12-01"}},
+		{Name: "expired-ttl", Labels: map[string]string{"ttl": "48h"}, CreationTimestamp: "2026-10-10T08:00:00.000-07:00"},
+		{Name: "fresh-ttl", Labels: map[string]string{"ttl": "7d"}, CreationTimestamp: "2026-10-15T08:00:00.000-07:00"},
+		{Name: "invalid-ttl", Labels: map[string]string{"ttl": "soon"}, CreationTimestamp: "2026-01-01T08:00:00.000-07:00"},
+		{Name: "unlabelled", CreationTimestamp: "2026-09-01T08:00:00.000-07:00"},
+	}
+	for _, disk := range disks {
+		server.Add(zone+"/disks", disk)
+	}
+	server.Add(zone+"/instanceGroupManagers", &compute.InstanceGroupManager{Name: "gke-pool", CreationTimestamp: "2026-01-01T08:00:00.000-07:00"})
+	server.Add("locations/"+testRegion+"/clusters", &container.Cluster{
+		Name:           "gke",
+		Location:       testRegion,
+		ResourceLabels: map[string]string{"ttl": "7d"},
+		CreateTime:     "2026-10-18T08:00:00+00:00",
+		NodePools:      []*container.NodePool{{Name: "pool", InstanceGroupUrls: []string{server.SelfLink(zone + "/instanceGroupManagers/gke-pool")}}},
+	})
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.TTLLabels = []string{"expires-at", "ttl"}
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]bool{
+		"expired-date": false,
+		"future-date":  true,
+		"expired-ttl":  false,
+		"fresh-ttl":    true,
+		"invalid-ttl":  true,
+		"unlabelled":   true,
+	}
+	for name, kept := range expected {
+		if server.Exists(zone+"/disks/"+name) != kept {
+			t.Errorf("expected disk %v to be kept: %v", name, kept)
+		}
+	}
+	if !server.Exists("locations/"+testRegion+"/clusters/gke") || !server.Exists(zone+"/instanceGroupManagers/gke-pool") {
+		t.Errorf("expected the cluster that hasn't expired to be kept with its node pool, remaining: %v", server.Paths())
+	}
+
+	// Unlabelled items expire after the default instead
+	testConfig.TTLDefault = 30 * 24 * time.Hour
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+	if server.Exists(zone + "/disks/unlabelled") {
+		t.Error("expected the unlabelled disk to be deleted once past the default ttl")
+	}
+	if !server.Exists(zone + "/disks/fresh-ttl") {
+		t.Error("expected the ttl label to take precedence over the default")
+	}
+}
+
+func TestRemoveProjectOwners(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	zone := "zones/" + testZone
+	server.Add(zone+"/disks", &compute.Disk{Name: "labelled", Labels: map[string]string{"owner": "alice", "team": "data"}})
+	server.Add(zone+"/disks", &compute.Disk{Name: "team", Labels: map[string]string{"team": "data"}})
+	server.Add(zone+"/disks", &compute.Disk{Name: "audited"})
+	server.Add(zone+"/disks", &compute.Disk{Name: "orphan"})
+	createdBy := "deploy-script"
+	server.Add(zone+"/instances", &compute.Instance{Name: "vm", Metadata: &compute.Metadata{Items: []*compute.MetadataItems{{Key: "created-by", Value: &createdBy}}}})
+
+	// One entry per line, as exported by a log sink, with an older creation of the same name
+	auditLog := filepath.Join(t.TempDir(), "audit.json")
+	entries := `{"timestamp": "2026-10-01T10:00:00Z", "protoPayload": {"methodName": "v1.compute.disks.insert", "resourceName": "projects/test-nuke-123456/zones/europe-west1-b/disks/audited", "authenticationInfo": {"principalEmail": "old@example.com"}}}
+{"timestamp": "2026-10-02T10:00:00Z", "protoPayload": {"methodName": "v1.compute.disks.insert", "resourceName": "projects/test-nuke-123456/zones/europe-west1-b/disks/audited", "authenticationInfo": {"principalEmail": "bob@example.com"}}}
+{"timestamp": "2026-10-03T10:00:00Z", "protoPayload": {"methodName": "v1.compute.disks.setLabels", "resourceName": "projects/test-nuke-123456/zones/europe-west1-b/disks/audited", "authenticationInfo": {"principalEmail": "mallory@example.com"}}}
+`
+	if err := ioutil.WriteFile(auditLog, []byte(entries), 0644); err != nil {
+		t.Fatal(err)
+	}
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.AuditLog = auditLog
+	testConfig.DryRun = true
+
+	runReport, err := RemoveProjectReport(testConfig, clients)
+	if err != nil {
+		t.Fatal(err)
+	}
+	owners := map[string]string{}
+	for _, owner := range runReport.ByOwner() {
+		for _, resource := range owner.Resources {
+			for _, item := range resource.Items {
+				owners[item] = owner.Owner
+			}
+		}
+	}
+	expected := map[string]string{
+		"labelled": "alice",
+		"team":     "data",
+		"audited":  "bob@example.com",
+		"orphan":   report.Unowned,
+		"vm":       "deploy-script",
+	}
+	if !reflect.DeepEqual(owners, expected) {
+		t.Errorf("expected owners %v, got: %v", expected, owners)
+	}
+	if byOwner := runReport.ByOwner(); byOwner[len(byOwner)-1].Owner != report.Unowned {
+		t.Errorf("expected unowned items last, got: %+v", byOwner)
+	}
+
+	testConfig.DryRun = false
+	testConfig.Owners = []string{"data", report.Unowned}
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, []string{zone + "/disks/audited", zone + "/disks/labelled", zone + "/instances/vm"}) {
+		t.Errorf("expected only the items of data and unowned items to be deleted, remaining: %v", remaining)
+	}
+}
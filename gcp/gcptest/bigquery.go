@@ -0,0 +1,81 @@
+package gcptest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// bigqueryPrefix - path of the bigquery api, followed by the project
+const bigqueryPrefix = "/bigquery/v2/projects/"
+
+// bigqueryCollections - the data transfer and reservation apis share the v1/projects/ path of the container api, told apart by their collections
+var bigqueryCollections = []string{"transferConfigs", "reservations"}
+
+func isBigQuery(path string) bool {
+	segments := strings.Split(path, "/")
+	if len(segments) < 3 || segments[0] != "locations" {
+		return false
+	}
+	for _, collection := range bigqueryCollections {
+		if segments[2] == collection {
+			return true
+		}
+	}
+	return false
+}
+
+// handleBigQuery - datasets with their tables and routines e.g. datasets/scratch/tables/events
+// A dataset which isn't empty is only deleted with deleteContents, as bigquery does
+func (s *Server) handleBigQuery(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		s.page(w, r, path, s.listPaths(path))
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		s.get(w, path)
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		if _, exists := s.resources[path]; !exists {
+			writeError(w, http.StatusNotFound, "notFound", "Not found: Dataset "+s.Project+":"+segments[1])
+			return
+		}
+		contents := append(s.listPaths(path+"/tables"), s.listPaths(path+"/routines")...)
+		if len(contents) > 0 && r.URL.Query().Get("deleteContents") != "true" {
+			writeError(w, http.StatusBadRequest, "resourceInUse", "Dataset "+s.Project+":"+segments[1]+" is still in use")
+			return
+		}
+		for _, contentPath := range contents {
+			delete(s.resources, contentPath)
+		}
+		delete(s.resources, path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown bigquery path "+path)
+	}
+}
+
+// handleBigQueryAdmin - transfer configs, and reservations with their assignments, kept under their location
+// e.g. locations/US/reservations/batch/assignments/1234. A reservation with assignments can't be deleted
+func (s *Server) handleBigQueryAdmin(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+
+	switch {
+	case len(segments)%2 == 1 && r.Method == http.MethodGet:
+		s.page(w, r, segments[len(segments)-1], s.listPaths(path))
+	case len(segments)%2 == 0 && r.Method == http.MethodGet:
+		s.get(w, path)
+	case len(segments)%2 == 0 && r.Method == http.MethodDelete:
+		if _, exists := s.resources[path]; !exists {
+			writeError(w, http.StatusNotFound, "notFound", "Requested entity was not found.")
+			return
+		}
+		if len(s.listPaths(path+"/assignments")) > 0 {
+			writeError(w, http.StatusBadRequest, "failedPrecondition", "Reservation "+path+" has assignments, delete them first")
+			return
+		}
+		delete(s.resources, path)
+		writeJSON(w, map[string]interface{}{})
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown bigquery path "+path)
+	}
+}
@@ -0,0 +1,180 @@
+package gcptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// dnsPrefix - path of the cloud dns api, followed by the project
+const dnsPrefix = "/dns/v1/projects/"
+
+// AddRecordSet - stores a record set in the managed zone, keyed by its name and type
+// e.g. AddRecordSet("zone-1", &dns.ResourceRecordSet{Name: "www.example.com.", Type: "A"}) is stored as managedZones/zone-1/rrsets/www.example.com.#A
+func (s *Server) AddRecordSet(zone string, recordSet interface{}) {
+	content, err := json.Marshal(recordSet)
+	if err != nil {
+		panic(err)
+	}
+	item := make(map[string]interface{})
+	if err := json.Unmarshal(content, &item); err != nil {
+		panic(err)
+	}
+	name, _ := item["name"].(string)
+	recordType, _ := item["type"].(string)
+	if name == "" || recordType == "" {
+		panic(fmt.Sprintf("gcptest: record set added to zone %v has no name or type", zone))
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.resources[recordSetPath(zone, name, recordType)] = item
+}
+
+func recordSetPath(zone, name, recordType string) string {
+	return "managedZones/" + zone + "/rrsets/" + name + "#" + recordType
+}
+
+// handleDNS - managed zones with their record sets and changes, server policies, and response policies with their rules
+func (s *Server) handleDNS(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		s.page(w, r, path, s.listPaths(path))
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		s.get(w, path)
+	case len(segments) == 2 && r.Method == http.MethodPatch:
+		s.patchDNS(w, r, path)
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		s.deleteDNS(w, path)
+	case len(segments) == 3 && segments[2] == "rrsets" && r.Method == http.MethodGet:
+		s.page(w, r, "rrsets", s.listPaths(path))
+	case len(segments) == 3 && segments[2] == "changes" && r.Method == http.MethodPost:
+		s.createChange(w, r, strings.Join(segments[:2], "/"))
+	case len(segments) == 4 && (segments[2] == "changes" || segments[2] == "operations"):
+		op, exists := s.operations[segments[3]]
+		if !exists {
+			writeError(w, http.StatusNotFound, "notFound", "The operation '"+path+"' was not found")
+			return
+		}
+		if !op.done {
+			op.pollsLeft--
+			if op.pollsLeft <= 0 {
+				s.completeOperation(op)
+			}
+		}
+		writeJSON(w, dnsOperationJSON(op))
+	case len(segments) == 3 && segments[2] == "rules" && r.Method == http.MethodGet:
+		s.page(w, r, "responsePolicyRules", s.listPaths(path))
+	case len(segments) == 4 && segments[2] == "rules" && r.Method == http.MethodDelete:
+		s.deleteNow(w, path)
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown dns path "+path)
+	}
+}
+
+// createChange - applies the deletions of the change once it is done, every record set deleted has to exist and not be a SOA or NS record of the apex
+func (s *Server) createChange(w http.ResponseWriter, r *http.Request, zonePath string) {
+	change := struct {
+		Deletions []struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"deletions"`
+	}{}
+	if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid", err.Error())
+		return
+	}
+	apex, _ := s.resources[zonePath]["dnsName"].(string)
+	paths := []string{}
+	for _, deletion := range change.Deletions {
+		if deletion.Name == apex && (deletion.Type == "SOA" || deletion.Type == "NS") {
+			writeError(w, http.StatusBadRequest, "invalidOperation", "The resource 'entity.change.deletions["+deletion.Name+"]["+deletion.Type+"]' is a required apex record and cannot be deleted.")
+			return
+		}
+		path := recordSetPath(strings.TrimPrefix(zonePath, "managedZones/"), deletion.Name, deletion.Type)
+		if _, exists := s.resources[path]; !exists {
+			writeError(w, http.StatusNotFound, "notFound", "The 'entity.change.deletions[0]' resource named '"+deletion.Name+" ("+deletion.Type+")' does not exist.")
+			return
+		}
+		paths = append(paths, path)
+	}
+	op := s.newOperation(zonePath, func() {
+		for _, path := range paths {
+			delete(s.resources, path)
+		}
+	})
+	writeJSON(w, dnsOperationJSON(op))
+}
+
+// patchDNS - sets the fields in the body, managed zone patches are operations and policy patches are immediate
+func (s *Server) patchDNS(w http.ResponseWriter, r *http.Request, path string) {
+	item, exists := s.resources[path]
+	if !exists {
+		writeError(w, http.StatusNotFound, "notFound", "The resource '"+path+"' was not found")
+		return
+	}
+	fields := make(map[string]interface{})
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid", err.Error())
+		return
+	}
+	update := func() {
+		for field, value := range fields {
+			item[field] = value
+		}
+	}
+	switch {
+	case strings.HasPrefix(path, "managedZones/"):
+		writeJSON(w, dnsOperationJSON(s.newOperation(path, update)))
+	case strings.HasPrefix(path, "responsePolicies/"):
+		update()
+		writeJSON(w, map[string]interface{}{"responsePolicy": item})
+	default:
+		update()
+		writeJSON(w, map[string]interface{}{"policy": item})
+	}
+}
+
+// deleteDNS - zones can only be deleted once only the SOA and NS records of their apex are left, and policies once detached
+func (s *Server) deleteDNS(w http.ResponseWriter, path string) {
+	item, exists := s.resources[path]
+	if !exists {
+		writeError(w, http.StatusNotFound, "notFound", "The resource '"+path+"' was not found")
+		return
+	}
+	if strings.HasPrefix(path, "managedZones/") {
+		apex, _ := item["dnsName"].(string)
+		recordSets := s.listPaths(path + "/rrsets")
+		for _, recordSetPath := range recordSets {
+			recordSet := s.resources[recordSetPath]
+			if recordSet["name"] != apex || (recordSet["type"] != "SOA" && recordSet["type"] != "NS") {
+				writeError(w, http.StatusBadRequest, "containerNotEmpty", "The resource named '"+path+"' cannot be deleted because it is not empty")
+				return
+			}
+		}
+		for _, recordSetPath := range recordSets {
+			delete(s.resources, recordSetPath)
+		}
+	} else {
+		if len(items(item["networks"])) > 0 || len(items(item["gkeClusters"])) > 0 {
+			writeError(w, http.StatusBadRequest, "resourceInUse", "The policy '"+path+"' cannot be deleted because it is still attached to networks")
+			return
+		}
+		if len(s.listPaths(path+"/rules")) > 0 {
+			writeError(w, http.StatusBadRequest, "containerNotEmpty", "The response policy '"+path+"' cannot be deleted because it still has rules")
+			return
+		}
+	}
+	s.deleteNow(w, path)
+}
+
+func dnsOperationJSON(op *operation) map[string]interface{} {
+	status := "pending"
+	if op.done {
+		status = "done"
+	}
+	return map[string]interface{}{"id": op.name, "status": status}
+}
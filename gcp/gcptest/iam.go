@@ -0,0 +1,55 @@
+package gcptest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// isIAM - iam shares the v1/projects/ path of the container api, told apart by its serviceAccounts collection
+func isIAM(path string) bool {
+	return strings.Split(path, "/")[0] == "serviceAccounts"
+}
+
+// handleIAM - service accounts and their keys, kept under the account email e.g. serviceAccounts/ci@p.iam.gserviceaccount.com/keys/1a2b
+// Deleting a service account deletes its keys, as iam does
+func (s *Server) handleIAM(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		s.page(w, r, "accounts", s.listPaths(path))
+	case len(segments) == 3 && segments[2] == "keys" && r.Method == http.MethodGet:
+		keyTypes := r.URL.Query()["keyTypes"]
+		keys := []interface{}{}
+		for _, keyPath := range s.listPaths(path) {
+			key := s.resources[keyPath]
+			if keyType, _ := key["keyType"].(string); len(keyTypes) == 0 || contains(keyTypes, keyType) {
+				keys = append(keys, key)
+			}
+		}
+		writeJSON(w, map[string]interface{}{"keys": keys})
+	case (len(segments) == 2 || len(segments) == 4) && r.Method == http.MethodGet:
+		s.get(w, path)
+	case (len(segments) == 2 || len(segments) == 4) && r.Method == http.MethodDelete:
+		if _, exists := s.resources[path]; !exists {
+			writeError(w, http.StatusNotFound, "notFound", "Resource '"+path+"' was not found")
+			return
+		}
+		for _, keyPath := range s.listPaths(path + "/keys") {
+			delete(s.resources, keyPath)
+		}
+		delete(s.resources, path)
+		writeJSON(w, map[string]interface{}{})
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown iam path "+path)
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
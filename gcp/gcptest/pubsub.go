@@ -0,0 +1,54 @@
+package gcptest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// pubsubCollections - pub/sub shares the v1/projects/ path of the container api, told apart by its collections
+var pubsubCollections = []string{"topics", "subscriptions", "snapshots", "schemas"}
+
+func isPubSub(path string) bool {
+	collection := strings.Split(path, "/")[0]
+	for _, pubsubCollection := range pubsubCollections {
+		if collection == pubsubCollection {
+			return true
+		}
+	}
+	return false
+}
+
+// handlePubSub - topics, subscriptions, snapshots and schemas, kept under their collection e.g. topics/orders
+// Deleting a topic leaves its subscriptions and snapshots behind, detached, as pub/sub does
+func (s *Server) handlePubSub(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		s.page(w, r, path, s.listPaths(path))
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		s.get(w, path)
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		if _, exists := s.resources[path]; !exists {
+			writeError(w, http.StatusNotFound, "notFound", "Resource not found (resource="+segments[1]+").")
+			return
+		}
+		delete(s.resources, path)
+		if segments[0] == "topics" {
+			s.detach(segments[1])
+		}
+		writeJSON(w, map[string]interface{}{})
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown pub/sub path "+path)
+	}
+}
+
+// detach - points the subscriptions and snapshots of a deleted topic at _deleted-topic_
+func (s *Server) detach(topic string) {
+	topicName := "projects/" + s.Project + "/topics/" + topic
+	for path, item := range s.resources {
+		if (strings.HasPrefix(path, "subscriptions/") || strings.HasPrefix(path, "snapshots/")) && item["topic"] == topicName {
+			item["topic"] = "_deleted-topic_"
+		}
+	}
+}
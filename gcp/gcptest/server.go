@@ -0,0 +1,636 @@
+// Package gcptest - an in-process fake of the compute, container, storage, cloud sql, cloud dns, pub/sub, cloud run, cloud functions, serverless vpc access, bigquery and iam REST APIs, for hermetic tests of gcp-nuke
+//
+// Resources are kept in memory, keyed by their path relative to the project e.g. zones/europe-west1-b/disks/disk-1
+// or locations/europe-west1/clusters/cluster-1. Buckets are kept under b/ as in the storage api e.g. b/bucket-1,
+// Cloud SQL instances under instances/ e.g. instances/db-1, and Cloud DNS zones under managedZones/ e.g. managedZones/zone-1.
+// Point config.Endpoint at Server.URL, with authentication disabled.
+package gcptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Server - fake compute, container, storage, cloud sql, cloud dns, pub/sub, cloud run, cloud functions, serverless vpc access, bigquery and iam API server
+type Server struct {
+	URL     string
+	Project string
+
+	// PageSize - maximum items per compute list page, 0 returns everything in one page
+	PageSize int
+	// OperationPolls - number of operation polls before an operation is DONE, 0 completes operations immediately
+	OperationPolls int
+
+	server     *httptest.Server
+	mutex      sync.Mutex
+	zones      []string
+	regions    []string
+	resources  map[string]map[string]interface{}
+	operations map[string]*operation
+	inUse      map[string]int
+	ghosts     map[string]int
+	slow       map[string]int
+	failures   map[string]failure
+	hangs      []string
+	requests   []string
+	opCounter  int
+}
+
+// operation - a fake long running operation, its effect is applied once it is DONE
+type operation struct {
+	name      string
+	pollsLeft int
+	done      bool
+	onDone    func()
+	// longRunning - a google.longrunning.Operation, done rather than DONE, of an api sharing the container api path
+	longRunning bool
+}
+
+// failure - an error every request under a path prefix fails with
+type failure struct {
+	code    int
+	reason  string
+	message string
+}
+
+// NewServer - starts a fake server for the project, call Close when finished
+func NewServer(project string, zones, regions []string) *Server {
+	s := &Server{
+		Project:    project,
+		zones:      zones,
+		regions:    regions,
+		resources:  make(map[string]map[string]interface{}),
+		operations: make(map[string]*operation),
+		inUse:      make(map[string]int),
+		ghosts:     make(map[string]int),
+		slow:       make(map[string]int),
+		failures:   make(map[string]failure),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close - shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Add - stores a resource in the collection e.g. Add("zones/europe-west1-b/disks", &compute.Disk{Name: "disk-1"})
+func (s *Server) Add(collection string, resource interface{}) {
+	content, err := json.Marshal(resource)
+	if err != nil {
+		panic(err)
+	}
+	item := make(map[string]interface{})
+	if err := json.Unmarshal(content, &item); err != nil {
+		panic(err)
+	}
+	name, _ := item["name"].(string)
+	// Some resources e.g. bucket notifications only have an id, or name their name field after themselves e.g. dns response policies
+	for _, field := range []string{"id", "responsePolicyName", "ruleName"} {
+		if name == "" {
+			name, _ = item[field].(string)
+		}
+	}
+	// BigQuery resources are named by their reference e.g. datasetReference.datasetId
+	for _, field := range []string{"dataset", "table", "routine"} {
+		if reference, ok := item[field+"Reference"].(map[string]interface{}); ok && name == "" {
+			name, _ = reference[field+"Id"].(string)
+		}
+	}
+	if name == "" {
+		panic(fmt.Sprintf("gcptest: resource added to %v has no name", collection))
+	}
+	// Full resource names e.g. projects/p/topics/orders of pub/sub are keyed by their last segment
+	path := collection + "/" + name[strings.LastIndex(name, "/")+1:]
+	if _, exists := item["selfLink"]; !exists {
+		item["selfLink"] = s.SelfLink(path)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.resources[path] = item
+}
+
+// Paths - paths of every resource still stored, sorted
+func (s *Server) Paths() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	paths := []string{}
+	for path := range s.resources {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// Exists - whether the resource at the path is still stored
+func (s *Server) Exists(path string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	_, exists := s.resources[path]
+	return exists
+}
+
+// Requests - every request served so far, as "METHOD path"
+func (s *Server) Requests() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.requests...)
+}
+
+// InUse - the next count deletes of the resource fail with resourceInUseByAnotherResource
+func (s *Server) InUse(path string, count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.inUse[path] = count
+}
+
+// Ghost - marks the resource as already deleted, it keeps being listed for count more lists whilst get and delete return 404
+func (s *Server) Ghost(path string, count int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if item, exists := s.resources[path]; exists {
+		item["deleted"] = true
+		s.ghosts[path] = count
+	}
+}
+
+// SlowOperation - deleting the resource starts an operation which needs polls polls before it is DONE
+func (s *Server) SlowOperation(path string, polls int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.slow[path] = polls
+}
+
+// Fail - every request to a path under the prefix e.g. /sql/v1beta4/projects/p/ fails with the code and reason, as for a disabled api or missing permission
+func (s *Server) Fail(pathPrefix string, code int, reason, message string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failures[pathPrefix] = failure{code: code, reason: reason, message: message}
+}
+
+// Hang - every request to a path under the prefix hangs until the client gives up on it
+func (s *Server) Hang(pathPrefix string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.hangs = append(s.hangs, pathPrefix)
+}
+
+// hung - whether the request hangs, checked without holding the mutex while hanging
+func (s *Server) hung(r *http.Request) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, pathPrefix := range s.hangs {
+		if strings.HasPrefix(r.URL.Path, pathPrefix) {
+			s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if s.hung(r) {
+		<-r.Context().Done()
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+	for pathPrefix, failure := range s.failures {
+		if strings.HasPrefix(r.URL.Path, pathPrefix) {
+			writeError(w, failure.code, failure.reason, failure.message)
+			return
+		}
+	}
+
+	computePrefix := "/compute/v1/projects/" + s.Project + "/"
+	containerPrefix := "/v1/projects/" + s.Project + "/"
+	switch {
+	case strings.HasPrefix(r.URL.Path, storagePrefix):
+		s.handleStorage(w, r)
+	case strings.HasPrefix(r.URL.Path, dnsPrefix+s.Project+"/"):
+		s.handleDNS(w, r, strings.TrimPrefix(r.URL.Path, dnsPrefix+s.Project+"/"))
+	case strings.HasPrefix(r.URL.Path, sqlPrefix+s.Project+"/"):
+		s.handleSQL(w, r, strings.TrimPrefix(r.URL.Path, sqlPrefix+s.Project+"/"))
+	case strings.HasPrefix(r.URL.Path, serverlessPrefix+s.Project+"/"):
+		s.handleServerless(w, r, strings.TrimPrefix(r.URL.Path, serverlessPrefix+s.Project+"/"))
+	case strings.HasPrefix(r.URL.Path, computePrefix):
+		s.handleCompute(w, r, strings.TrimPrefix(r.URL.Path, computePrefix))
+	case strings.HasPrefix(r.URL.Path, bigqueryPrefix+s.Project+"/"):
+		s.handleBigQuery(w, r, strings.TrimPrefix(r.URL.Path, bigqueryPrefix+s.Project+"/"))
+	case strings.HasPrefix(r.URL.Path, containerPrefix) && isBigQuery(strings.TrimPrefix(r.URL.Path, containerPrefix)):
+		s.handleBigQueryAdmin(w, r, strings.TrimPrefix(r.URL.Path, containerPrefix))
+	case strings.HasPrefix(r.URL.Path, containerPrefix) && isIAM(strings.TrimPrefix(r.URL.Path, containerPrefix)):
+		s.handleIAM(w, r, strings.TrimPrefix(r.URL.Path, containerPrefix))
+	case strings.HasPrefix(r.URL.Path, containerPrefix) && isVPCAccess(strings.TrimPrefix(r.URL.Path, containerPrefix)):
+		s.handleVPCAccess(w, r, strings.TrimPrefix(r.URL.Path, containerPrefix))
+	case strings.HasPrefix(r.URL.Path, containerPrefix) && isPubSub(strings.TrimPrefix(r.URL.Path, containerPrefix)):
+		s.handlePubSub(w, r, strings.TrimPrefix(r.URL.Path, containerPrefix))
+	case strings.HasPrefix(r.URL.Path, containerPrefix):
+		s.handleContainer(w, r, strings.TrimPrefix(r.URL.Path, containerPrefix))
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown path "+r.URL.Path)
+	}
+}
+
+func (s *Server) handleCompute(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+	scopeLength := 1
+	if segments[0] == "zones" || segments[0] == "regions" {
+		scopeLength = 2
+	}
+
+	switch {
+	case path == "zones" || path == "regions":
+		names := s.zones
+		if path == "regions" {
+			names = s.regions
+		}
+		items := []interface{}{}
+		for _, name := range names {
+			items = append(items, map[string]interface{}{"name": name, "selfLink": s.SelfLink(path + "/" + name)})
+		}
+		writeJSON(w, map[string]interface{}{"items": items})
+	case len(segments) == scopeLength+1 && r.Method == http.MethodGet:
+		s.list(w, r, path)
+	case len(segments) == scopeLength+2 && segments[scopeLength] == "operations":
+		s.getOperation(w, path, false)
+	case len(segments) == scopeLength+2 && r.Method == http.MethodGet:
+		s.get(w, path)
+	case len(segments) == scopeLength+2 && r.Method == http.MethodDelete:
+		s.delete(w, path, false)
+	case len(segments) == scopeLength+3 && r.Method == http.MethodPost:
+		s.action(w, r, strings.Join(segments[:scopeLength+2], "/"), segments[scopeLength+2])
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown compute path "+path)
+	}
+}
+
+func (s *Server) handleContainer(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+
+	switch {
+	case len(segments) == 3 && segments[2] == "clusters" && r.Method == http.MethodGet:
+		clusters := []interface{}{}
+		for _, itemPath := range s.listPaths("locations/" + segments[1] + "/clusters") {
+			clusters = append(clusters, s.resources[itemPath])
+		}
+		writeJSON(w, map[string]interface{}{"clusters": clusters})
+	case len(segments) == 4 && segments[2] == "operations":
+		s.getOperation(w, path, true)
+	case len(segments) == 4 && segments[2] == "clusters" && r.Method == http.MethodDelete:
+		s.delete(w, path, true)
+	case len(segments) == 5 && segments[4] == "nodePools" && r.Method == http.MethodGet:
+		cluster, exists := s.resources[strings.Join(segments[:4], "/")]
+		if !exists {
+			writeError(w, http.StatusNotFound, "notFound", "cluster not found "+path)
+			return
+		}
+		nodePools := cluster["nodePools"]
+		if nodePools == nil {
+			nodePools = []interface{}{}
+		}
+		writeJSON(w, map[string]interface{}{"nodePools": nodePools})
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown container path "+path)
+	}
+}
+
+// list - a page of the collection, "-" as a location or zone matches every location
+func (s *Server) list(w http.ResponseWriter, r *http.Request, collection string) {
+	s.page(w, r, "items", s.listPaths(collection))
+}
+
+// page - the items at the paths from the pageToken on, at most PageSize of them, in the field of the list response
+func (s *Server) page(w http.ResponseWriter, r *http.Request, field string, paths []string) {
+	offset := 0
+	if token := r.URL.Query().Get("pageToken"); token != "" {
+		offset, _ = strconv.Atoi(token)
+	}
+	end := len(paths)
+	if s.PageSize > 0 && offset+s.PageSize < end {
+		end = offset + s.PageSize
+	}
+
+	items := []interface{}{}
+	for _, path := range paths[offset:end] {
+		items = append(items, s.resources[path])
+	}
+	response := map[string]interface{}{field: items}
+	if end < len(paths) {
+		response["nextPageToken"] = strconv.Itoa(end)
+	}
+	writeJSON(w, response)
+}
+
+// listPaths - sorted paths of the items in a collection, counting down any ghosts
+func (s *Server) listPaths(collection string) []string {
+	segments := strings.Split(collection, "/")
+	paths := []string{}
+	for path := range s.resources {
+		pathSegments := strings.Split(path, "/")
+		if len(pathSegments) != len(segments)+1 {
+			continue
+		}
+		matches := true
+		for i, segment := range segments {
+			if segment != "-" && segment != pathSegments[i] {
+				matches = false
+			}
+		}
+		if matches {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	listed := []string{}
+	for _, path := range paths {
+		if s.resources[path]["deleted"] == true {
+			// A deleted ghost is listed until its count runs out
+			if s.ghosts[path] <= 0 {
+				delete(s.resources, path)
+				delete(s.ghosts, path)
+				continue
+			}
+			s.ghosts[path]--
+		}
+		listed = append(listed, path)
+	}
+	return listed
+}
+
+func (s *Server) get(w http.ResponseWriter, path string) {
+	item, exists := s.resources[path]
+	if !exists || item["deleted"] == true {
+		writeError(w, http.StatusNotFound, "notFound", "The resource '"+path+"' was not found")
+		return
+	}
+	writeJSON(w, item)
+}
+
+func (s *Server) delete(w http.ResponseWriter, path string, container bool) {
+	item, exists := s.resources[path]
+	if !exists || item["deleted"] == true {
+		writeError(w, http.StatusNotFound, "notFound", "The resource '"+path+"' was not found")
+		return
+	}
+	if s.inUse[path] > 0 {
+		s.inUse[path]--
+		writeError(w, http.StatusBadRequest, "resourceInUseByAnotherResource", "The resource '"+path+"' is already being used")
+		return
+	}
+	if user := s.referencedBy(path); user != "" {
+		writeError(w, http.StatusBadRequest, "resourceInUseByAnotherResource", "The resource '"+path+"' is already being used by '"+user+"'")
+		return
+	}
+
+	op := s.newOperation(path, func() {
+		s.deleteResource(path)
+	})
+	writeJSON(w, s.operationJSON(op, container))
+}
+
+// action - custom compute methods on a resource e.g. setDiskAutoDelete
+func (s *Server) action(w http.ResponseWriter, r *http.Request, path, method string) {
+	item, exists := s.resources[path]
+	if !exists || item["deleted"] == true {
+		writeError(w, http.StatusNotFound, "notFound", "The resource '"+path+"' was not found")
+		return
+	}
+
+	var effect func()
+	switch method {
+	case "setDiskAutoDelete":
+		deviceName := r.URL.Query().Get("deviceName")
+		autoDelete := r.URL.Query().Get("autoDelete") == "true"
+		effect = func() {
+			for _, disk := range items(item["disks"]) {
+				if disk["deviceName"] == deviceName {
+					disk["autoDelete"] = autoDelete
+				}
+			}
+		}
+	case "createSnapshot":
+		snapshot := map[string]interface{}{}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &snapshot); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid", err.Error())
+			return
+		}
+		snapshotPath := "global/snapshots/" + snapshot["name"].(string)
+		if _, exists := s.resources[snapshotPath]; exists {
+			writeError(w, http.StatusConflict, "alreadyExists", "The resource '"+snapshotPath+"' already exists")
+			return
+		}
+		effect = func() {
+			snapshot["sourceDisk"] = s.SelfLink(path)
+			snapshot["selfLink"] = s.SelfLink(snapshotPath)
+			s.resources[snapshotPath] = snapshot
+		}
+	case "removePeering":
+		request := map[string]interface{}{}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &request); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid", err.Error())
+			return
+		}
+		effect = func() {
+			peerings := []interface{}{}
+			for _, peering := range items(item["peerings"]) {
+				if peering["name"] != request["name"] {
+					peerings = append(peerings, peering)
+				}
+			}
+			item["peerings"] = peerings
+		}
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown method "+method)
+		return
+	}
+	writeJSON(w, s.operationJSON(s.newOperation(path, effect), false))
+}
+
+// deleteResource - removes the resource along with anything google would delete with it
+func (s *Server) deleteResource(path string) {
+	item := s.resources[path]
+	delete(s.resources, path)
+
+	// Instances take their auto delete disks with them
+	for _, disk := range items(item["disks"]) {
+		if disk["autoDelete"] == true {
+			if source, ok := disk["source"].(string); ok {
+				delete(s.resources, s.relativePath(source))
+			}
+		}
+	}
+	// GKE clusters take their node pool instance groups with them
+	for _, nodePool := range items(item["nodePools"]) {
+		urls, _ := nodePool["instanceGroupUrls"].([]interface{})
+		for _, url := range urls {
+			if url, ok := url.(string); ok {
+				delete(s.resources, s.relativePath(url))
+			}
+		}
+	}
+}
+
+// referencedBy - path of another resource which refers to the resource, e.g. a subnetwork in a network
+func (s *Server) referencedBy(path string) string {
+	for otherPath, item := range s.resources {
+		if otherPath == path || item["deleted"] == true {
+			continue
+		}
+		if s.references(item, path, true) {
+			return otherPath
+		}
+	}
+	return s.connectorUsing(path)
+}
+
+func (s *Server) references(value interface{}, path string, topLevel bool) bool {
+	switch value := value.(type) {
+	case string:
+		return strings.HasSuffix(value, "/projects/"+s.Project+"/"+path)
+	case []interface{}:
+		for _, v := range value {
+			if s.references(v, path, false) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		for key, v := range value {
+			// Disk users point back at their instances, node pool instance groups are owned by the cluster, and snapshots outlive their disk
+			if (topLevel && key == "selfLink") || key == "users" || key == "instanceGroupUrls" || key == "sourceDisk" {
+				continue
+			}
+			if s.references(v, path, false) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Server) newOperation(path string, onDone func()) *operation {
+	s.opCounter++
+	op := &operation{
+		name:      fmt.Sprintf("operation-%d", s.opCounter),
+		pollsLeft: s.OperationPolls,
+		onDone:    onDone,
+	}
+	if polls, slow := s.slow[path]; slow {
+		op.pollsLeft = polls
+	}
+	s.operations[op.name] = op
+	if op.pollsLeft == 0 {
+		s.completeOperation(op)
+	}
+	return op
+}
+
+func (s *Server) completeOperation(op *operation) {
+	op.done = true
+	if op.onDone != nil {
+		op.onDone()
+	}
+}
+
+func (s *Server) getOperation(w http.ResponseWriter, path string, container bool) {
+	segments := strings.Split(path, "/")
+	op, exists := s.operations[segments[len(segments)-1]]
+	if !exists {
+		writeError(w, http.StatusNotFound, "notFound", "The operation '"+path+"' was not found")
+		return
+	}
+	if !op.done {
+		op.pollsLeft--
+		if op.pollsLeft <= 0 {
+			s.completeOperation(op)
+		}
+	}
+	if op.longRunning {
+		writeJSON(w, s.longRunningOperationJSON(segments[1], op))
+		return
+	}
+	writeJSON(w, s.operationJSON(op, container))
+}
+
+func (s *Server) operationJSON(op *operation, container bool) map[string]interface{} {
+	status := "RUNNING"
+	if op.done {
+		status = "DONE"
+	}
+	response := map[string]interface{}{"name": op.name, "status": status}
+	if !container {
+		response["kind"] = "compute#operation"
+	}
+	return response
+}
+
+// SelfLink - url of the resource at the path, as the api would return it
+func (s *Server) SelfLink(path string) string {
+	if strings.HasPrefix(path, "b/") {
+		return s.URL + storagePrefix + path
+	}
+	if strings.HasPrefix(path, "managedZones/") || strings.HasPrefix(path, "policies/") || strings.HasPrefix(path, "responsePolicies/") {
+		return s.URL + dnsPrefix + s.Project + "/" + path
+	}
+	if strings.HasPrefix(path, "datasets/") {
+		return s.URL + bigqueryPrefix + s.Project + "/" + path
+	}
+	if strings.HasPrefix(path, "instances/") {
+		return s.URL + sqlPrefix + s.Project + "/" + path
+	}
+	if strings.HasPrefix(path, "locations/") {
+		return s.URL + "/v1/projects/" + s.Project + "/" + path
+	}
+	return s.URL + "/compute/v1/projects/" + s.Project + "/" + path
+}
+
+// relativePath - path relative to the project of a resource url
+func (s *Server) relativePath(url string) string {
+	split := strings.SplitN(url, "/projects/"+s.Project+"/", 2)
+	return split[len(split)-1]
+}
+
+func items(value interface{}) []map[string]interface{} {
+	list, _ := value.([]interface{})
+	output := []map[string]interface{}{}
+	for _, v := range list {
+		if item, ok := v.(map[string]interface{}); ok {
+			output = append(output, item)
+		}
+	}
+	return output
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError - error body in the format google apis return, so googleapi.Error carries the reason
+func writeError(w http.ResponseWriter, code int, reason, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    code,
+			"message": message,
+			"errors": []interface{}{
+				map[string]interface{}{"reason": reason, "message": message},
+			},
+		},
+	})
+}
@@ -0,0 +1,60 @@
+package gcptest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// serverlessPrefix - path of the cloud run admin and cloud functions v2 apis, followed by the project
+const serverlessPrefix = "/v2/projects/"
+
+// handleServerless - cloud run services and jobs, and cloud functions, kept under their location e.g. locations/europe-west1/services/api
+// Both apis share the path and long running operations, deleting a 2nd gen function deletes its cloud run service
+func (s *Server) handleServerless(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+
+	switch {
+	case len(segments) == 3 && r.Method == http.MethodGet:
+		s.page(w, r, segments[2], s.listPaths(path))
+	case len(segments) == 4 && segments[2] == "operations":
+		op, exists := s.operations[segments[3]]
+		if !exists {
+			writeError(w, http.StatusNotFound, "notFound", "The operation '"+path+"' was not found")
+			return
+		}
+		if !op.done {
+			op.pollsLeft--
+			if op.pollsLeft <= 0 {
+				s.completeOperation(op)
+			}
+		}
+		writeJSON(w, s.longRunningOperationJSON(segments[1], op))
+	case len(segments) == 4 && r.Method == http.MethodGet:
+		s.get(w, path)
+	case len(segments) == 4 && r.Method == http.MethodDelete:
+		item, exists := s.resources[path]
+		if !exists {
+			writeError(w, http.StatusNotFound, "notFound", "Resource '"+path+"' was not found")
+			return
+		}
+		op := s.newOperation(path, func() {
+			delete(s.resources, path)
+			if serviceConfig, ok := item["serviceConfig"].(map[string]interface{}); ok {
+				if service, ok := serviceConfig["service"].(string); ok {
+					delete(s.resources, strings.TrimPrefix(service, "projects/"+s.Project+"/"))
+				}
+			}
+		})
+		writeJSON(w, s.longRunningOperationJSON(segments[1], op))
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown serverless path "+path)
+	}
+}
+
+// longRunningOperationJSON - a google.longrunning.Operation in the location, done rather than a status
+func (s *Server) longRunningOperationJSON(location string, op *operation) map[string]interface{} {
+	return map[string]interface{}{
+		"name": "projects/" + s.Project + "/locations/" + location + "/operations/" + op.name,
+		"done": op.done,
+	}
+}
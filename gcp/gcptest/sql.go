@@ -0,0 +1,111 @@
+package gcptest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// sqlPrefix - path of the cloud sql admin api, followed by the project
+const sqlPrefix = "/sql/v1beta4/projects/"
+
+// handleSQL - cloud sql instances and their operations, instances are kept under instances/ e.g. instances/db-1
+func (s *Server) handleSQL(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+
+	switch {
+	case path == "instances" && r.Method == http.MethodGet:
+		s.list(w, r, "instances")
+	case len(segments) == 2 && segments[0] == "operations":
+		op, exists := s.operations[segments[1]]
+		if !exists {
+			writeError(w, http.StatusNotFound, "notFound", "The operation '"+path+"' was not found")
+			return
+		}
+		if !op.done {
+			op.pollsLeft--
+			if op.pollsLeft <= 0 {
+				s.completeOperation(op)
+			}
+		}
+		writeJSON(w, sqlOperationJSON(op))
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		s.get(w, path)
+	case len(segments) == 2 && r.Method == http.MethodPatch:
+		s.patchSQLInstance(w, r, path)
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		s.deleteSQLInstance(w, path)
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown sql path "+path)
+	}
+}
+
+// patchSQLInstance - merges the settings in the body into the instance settings once the operation is DONE
+func (s *Server) patchSQLInstance(w http.ResponseWriter, r *http.Request, path string) {
+	item, exists := s.resources[path]
+	if !exists {
+		writeError(w, http.StatusNotFound, "notFound", "The Cloud SQL instance does not exist.")
+		return
+	}
+	patch := make(map[string]interface{})
+	if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid", err.Error())
+		return
+	}
+	op := s.newOperation(path, func() {
+		settings, _ := item["settings"].(map[string]interface{})
+		if settings == nil {
+			settings = make(map[string]interface{})
+			item["settings"] = settings
+		}
+		patchSettings, _ := patch["settings"].(map[string]interface{})
+		for field, value := range patchSettings {
+			settings[field] = value
+		}
+	})
+	writeJSON(w, sqlOperationJSON(op))
+}
+
+// deleteSQLInstance - instances with deletion protection or read replicas can't be deleted
+func (s *Server) deleteSQLInstance(w http.ResponseWriter, path string) {
+	item, exists := s.resources[path]
+	if !exists {
+		writeError(w, http.StatusNotFound, "notFound", "The Cloud SQL instance does not exist.")
+		return
+	}
+	if settings, _ := item["settings"].(map[string]interface{}); settings["deletionProtectionEnabled"] == true {
+		writeError(w, http.StatusBadRequest, "deletionProtectionEnabled", "The instance is protected. Please disable the deletion protection and try again.")
+		return
+	}
+	if replicas, _ := item["replicaNames"].([]interface{}); len(replicas) > 0 {
+		writeError(w, http.StatusBadRequest, "invalidState", "The instance has read replicas, delete them before the primary instance.")
+		return
+	}
+
+	name := strings.TrimPrefix(path, "instances/")
+	op := s.newOperation(path, func() {
+		delete(s.resources, path)
+		// The replica drops out of the replicaNames of its primary
+		master, _ := item["masterInstanceName"].(string)
+		master = master[strings.LastIndex(master, ":")+1:]
+		if primary, exists := s.resources["instances/"+master]; exists {
+			existing, _ := primary["replicaNames"].([]interface{})
+			replicas := []interface{}{}
+			for _, replica := range existing {
+				if replica != name {
+					replicas = append(replicas, replica)
+				}
+			}
+			primary["replicaNames"] = replicas
+		}
+	})
+	writeJSON(w, sqlOperationJSON(op))
+}
+
+func sqlOperationJSON(op *operation) map[string]interface{} {
+	status := "RUNNING"
+	if op.done {
+		status = "DONE"
+	}
+	return map[string]interface{}{"kind": "sql#operation", "name": op.name, "status": status}
+}
@@ -0,0 +1,161 @@
+package gcptest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// storagePrefix - path of the storage api, buckets aren't scoped by project in their paths
+const storagePrefix = "/storage/v1/"
+
+// AddObject - stores a version of an object in the bucket, keyed by its name and generation
+// e.g. AddObject("bucket-1", &storage.Object{Name: "logs/a.txt", Generation: 2}) is stored as b/bucket-1/o/logs/a.txt#2
+func (s *Server) AddObject(bucket string, object interface{}) {
+	content, err := json.Marshal(object)
+	if err != nil {
+		panic(err)
+	}
+	item := make(map[string]interface{})
+	if err := json.Unmarshal(content, &item); err != nil {
+		panic(err)
+	}
+	name, _ := item["name"].(string)
+	generation, _ := item["generation"].(string)
+	if name == "" || generation == "" {
+		panic(fmt.Sprintf("gcptest: object added to bucket %v has no name or generation", bucket))
+	}
+	item["bucket"] = bucket
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.resources[objectPath(bucket, name, generation)] = item
+}
+
+func objectPath(bucket, name, generation string) string {
+	return "b/" + bucket + "/o/" + name + "#" + generation
+}
+
+// handleStorage - buckets, their objects and notification configs
+func (s *Server) handleStorage(w http.ResponseWriter, r *http.Request) {
+	// Object names are escaped in the path, and may contain slashes once unescaped
+	segments := strings.Split(strings.TrimPrefix(r.URL.EscapedPath(), storagePrefix), "/")
+	for i, segment := range segments {
+		segments[i], _ = url.PathUnescape(segment)
+	}
+	if segments[0] != "b" {
+		writeError(w, http.StatusNotFound, "notFound", "unknown storage path "+r.URL.Path)
+		return
+	}
+	bucketPath := ""
+	if len(segments) >= 2 {
+		bucketPath = "b/" + segments[1]
+	}
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		s.list(w, r, "b")
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		s.get(w, bucketPath)
+	case len(segments) == 2 && r.Method == http.MethodPatch:
+		s.patch(w, r, bucketPath)
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		s.deleteBucket(w, bucketPath)
+	case len(segments) == 3 && segments[2] == "o" && r.Method == http.MethodGet:
+		s.page(w, r, "items", s.objectPaths(bucketPath, r.URL.Query().Get("versions") == "true"))
+	case len(segments) == 4 && segments[2] == "o" && r.Method == http.MethodDelete:
+		s.deleteObject(w, objectPath(segments[1], segments[3], r.URL.Query().Get("generation")))
+	case len(segments) == 3 && segments[2] == "notificationConfigs" && r.Method == http.MethodGet:
+		s.list(w, r, bucketPath+"/notificationConfigs")
+	case len(segments) == 4 && segments[2] == "notificationConfigs" && r.Method == http.MethodDelete:
+		s.deleteNow(w, strings.Join(segments, "/"))
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown storage path "+r.URL.Path)
+	}
+}
+
+// objectPaths - sorted paths of the objects in the bucket, only the live versions unless versions is set
+func (s *Server) objectPaths(bucketPath string, versions bool) []string {
+	paths := []string{}
+	for path, item := range s.resources {
+		if !strings.HasPrefix(path, bucketPath+"/o/") {
+			continue
+		}
+		if _, noncurrent := item["timeDeleted"]; noncurrent && !versions {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// patch - sets the fields in the body, null fields are removed
+func (s *Server) patch(w http.ResponseWriter, r *http.Request, path string) {
+	item, exists := s.resources[path]
+	if !exists {
+		writeError(w, http.StatusNotFound, "notFound", "The resource '"+path+"' was not found")
+		return
+	}
+	fields := make(map[string]interface{})
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid", err.Error())
+		return
+	}
+	for field, value := range fields {
+		if value == nil {
+			delete(item, field)
+			continue
+		}
+		item[field] = value
+	}
+	writeJSON(w, item)
+}
+
+// deleteBucket - buckets can only be deleted once every object version is gone
+func (s *Server) deleteBucket(w http.ResponseWriter, path string) {
+	if len(s.objectPaths(path, true)) > 0 {
+		writeError(w, http.StatusConflict, "conflict", "The bucket you tried to delete is not empty.")
+		return
+	}
+	for notificationPath := range s.resources {
+		if strings.HasPrefix(notificationPath, path+"/notificationConfigs/") {
+			delete(s.resources, notificationPath)
+		}
+	}
+	s.deleteNow(w, path)
+}
+
+// deleteObject - objects under a hold or retention period can't be deleted
+func (s *Server) deleteObject(w http.ResponseWriter, path string) {
+	item, exists := s.resources[path]
+	if !exists {
+		writeError(w, http.StatusNotFound, "notFound", "No such object: "+path)
+		return
+	}
+	if item["eventBasedHold"] == true || item["temporaryHold"] == true {
+		writeError(w, http.StatusForbidden, "forbidden", "Object '"+path+"' is under active hold and cannot be deleted")
+		return
+	}
+	if expiry, _ := item["retentionExpirationTime"].(string); expiry != "" {
+		if retainedUntil, err := time.Parse(time.RFC3339, expiry); err == nil && retainedUntil.After(time.Now()) {
+			writeError(w, http.StatusForbidden, "retentionPolicyNotMet", "Object '"+path+"' is subject to bucket's retention policy and cannot be deleted")
+			return
+		}
+	}
+	s.deleteNow(w, path)
+}
+
+// deleteNow - deletions which are immediate, with an empty response e.g. of buckets and objects
+func (s *Server) deleteNow(w http.ResponseWriter, path string) {
+	if _, exists := s.resources[path]; !exists {
+		writeError(w, http.StatusNotFound, "notFound", "The resource '"+path+"' was not found")
+		return
+	}
+	delete(s.resources, path)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,55 @@
+package gcptest
+
+import (
+	"net/http"
+	"strings"
+)
+
+// isVPCAccess - serverless vpc access shares the v1/projects/ path of the container api, told apart by its connectors collection
+func isVPCAccess(path string) bool {
+	segments := strings.Split(path, "/")
+	return len(segments) >= 3 && segments[0] == "locations" && segments[2] == "connectors"
+}
+
+// handleVPCAccess - connectors, kept under their location e.g. locations/europe-west1/connectors/connector-1
+// Their operations are long running operations polled through the container api path, see getOperation
+func (s *Server) handleVPCAccess(w http.ResponseWriter, r *http.Request, path string) {
+	segments := strings.Split(path, "/")
+
+	switch {
+	case len(segments) == 3 && r.Method == http.MethodGet:
+		s.page(w, r, "connectors", s.listPaths(path))
+	case len(segments) == 4 && r.Method == http.MethodGet:
+		s.get(w, path)
+	case len(segments) == 4 && r.Method == http.MethodDelete:
+		if _, exists := s.resources[path]; !exists {
+			writeError(w, http.StatusNotFound, "notFound", "Resource '"+path+"' was not found")
+			return
+		}
+		op := s.newOperation(path, func() {
+			delete(s.resources, path)
+		})
+		op.longRunning = true
+		writeJSON(w, s.longRunningOperationJSON(segments[1], op))
+	default:
+		writeError(w, http.StatusNotFound, "notFound", "unknown vpc access path "+path)
+	}
+}
+
+// connectorUsing - path of a connector in the network or subnetwork at the path, connectors name them without a url
+func (s *Server) connectorUsing(path string) string {
+	segments := strings.Split(path, "/")
+	for otherPath, item := range s.resources {
+		if !isVPCAccess(otherPath) {
+			continue
+		}
+		subnet, _ := item["subnet"].(map[string]interface{})
+		switch {
+		case len(segments) == 3 && segments[1] == "networks" && item["network"] == segments[2]:
+			return otherPath
+		case len(segments) == 4 && segments[2] == "subnetworks" && subnet != nil && subnet["name"] == segments[3] && strings.Split(otherPath, "/")[1] == segments[1]:
+			return otherPath
+		}
+	}
+	return ""
+}
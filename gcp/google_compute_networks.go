@@ -1,14 +1,12 @@
 package gcp
 
 import (
+	"context"
 	"fmt"
-	"log"
 	"sync"
-	"time"
 
 	"github.com/arehmandev/gcp-nuke/config"
 	"github.com/arehmandev/gcp-nuke/helpers"
-	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/syncmap"
 	"google.golang.org/api/compute/v1"
 )
@@ -21,14 +19,9 @@ type ComputeNetworks struct {
 }
 
 func init() {
-	computeService, err := compute.NewService(Ctx)
-	if err != nil {
-		log.Fatal(err)
-	}
-	computeResource := ComputeNetworks{
-		serviceClient: computeService,
-	}
-	register(&computeResource)
+	register(func() Resource {
+		return &ComputeNetworks{}
+	})
 }
 
 // Name - Name of the resourceLister for ComputeNetworks
@@ -43,74 +36,78 @@ func (c *ComputeNetworks) ToSlice() (slice []string) {
 }
 
 // Setup - populates the struct
-func (c *ComputeNetworks) Setup(config config.Config) {
+func (c *ComputeNetworks) Setup(config config.Config, clients *Clients) (err error) {
 	c.base.config = config
-
+	c.serviceClient, err = clients.Compute()
+	return err
 }
 
 // List - Returns a list of all ComputeNetworks
-func (c *ComputeNetworks) List(refreshCache bool) []string {
+func (c *ComputeNetworks) List(ctx context.Context, refreshCache bool) ([]string, error) {
 	if !refreshCache {
-		return c.ToSlice()
+		return c.ToSlice(), nil
 	}
 	// Refresh resource map
 	c.resourceMap = sync.Map{}
 
 	networkListCall := c.serviceClient.Networks.List(c.base.config.Project)
-	networkList, err := networkListCall.Do()
+	err := networkListCall.Pages(ctx, func(networkList *compute.NetworkList) error {
+		for _, network := range networkList.Items {
+			if c.base.skip(c.Name(), listedItem{name: network.Name, created: network.CreationTimestamp}) {
+				continue
+			}
+			c.resourceMap.Store(network.Name, nil)
+		}
+		return nil
+	})
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
-
-	for _, network := range networkList.Items {
-		c.resourceMap.Store(network.Name, nil)
-	}
-	return c.ToSlice()
+	return c.ToSlice(), nil
 }
 
 // Dependencies - Returns a List of resource names to check for
 func (c *ComputeNetworks) Dependencies() []string {
 	a := ComputeSubnetworks{}
-	return []string{a.Name()}
+	b := ComputeGlobalForwardingRules{}
+	d := ComputeGlobalAddresses{}
+	// Private zones and policies attached to the network fail its deletion with "in use by DNS policy"
+	e := DNSManagedZones{}
+	f := DNSPolicies{}
+	// Connectors with an ip range rather than a subnetwork hold a /28 of the network
+	g := VPCAccessConnectors{}
+	return []string{a.Name(), b.Name(), d.Name(), e.Name(), f.Name(), g.Name()}
 }
 
 // Remove -
-func (c *ComputeNetworks) Remove() error {
+func (c *ComputeNetworks) Remove(ctx context.Context) error {
 
 	// Removal logic
-	errs, _ := errgroup.WithContext(c.base.config.Context)
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
 
 	c.resourceMap.Range(func(key, value interface{}) bool {
 		networkID := key.(string)
 
 		// Parallel network deletion
-		errs.Go(func() error {
+		errs.Go(networkID, "global", func(ctx context.Context) error {
 			deleteCall := c.serviceClient.Networks.Delete(c.base.config.Project, networkID)
-			operation, err := deleteCall.Do()
+			operation, err := deleteCall.Context(ctx).Do()
 			if err != nil {
 				return err
 			}
-			var opStatus string
-			seconds := 0
-			for opStatus != "DONE" {
-				log.Printf("[Info] Resource currently being deleted %v [type: %v project: %v] (%v seconds)", networkID, c.Name(), c.base.config.Project, seconds)
-
+			description := fmt.Sprintf("%v [type: %v project: %v]", networkID, c.Name(), c.base.config.Project)
+			err = c.base.waitForOperation(ctx, c.Name(), description, func() (string, error) {
 				operationCall := c.serviceClient.GlobalOperations.Get(c.base.config.Project, operation.Name)
-				checkOpp, err := operationCall.Do()
+				checkOpp, err := operationCall.Context(ctx).Do()
 				if err != nil {
-					return err
-				}
-				opStatus = checkOpp.Status
-
-				time.Sleep(time.Duration(c.base.config.PollTime) * time.Second)
-				seconds += c.base.config.PollTime
-				if seconds > c.base.config.Timeout {
-					return fmt.Errorf("[Error] Resource deletion timed out for %v [type: %v project: %v] (%v seconds)", networkID, c.Name(), c.base.config.Project, c.base.config.Timeout)
+					return "", err
 				}
+				return checkOpp.Status, nil
+			})
+			if err != nil {
+				return err
 			}
 			c.resourceMap.Delete(networkID)
-
-			log.Printf("[Info] Resource deleted %v [type: %v project: %v] (%v seconds)", networkID, c.Name(), c.base.config.Project, seconds)
 			return nil
 		})
 		return true
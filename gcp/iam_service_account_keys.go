@@ -0,0 +1,132 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"github.com/arehmandev/gcp-nuke/report"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/iam/v1"
+)
+
+// IAMServiceAccountKeys - user-managed keys of the service accounts IAMServiceAccounts would delete, keyed by account id/key id
+// The age and expiry of every key is recorded in the report as a note, so the keys can be audited from the plan
+type IAMServiceAccountKeys struct {
+	serviceClient *iam.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &IAMServiceAccountKeys{}
+	})
+}
+
+// Name - Name of the resourceLister for IAMServiceAccountKeys
+func (c *IAMServiceAccountKeys) Name() string {
+	return "IAMServiceAccountKeys"
+}
+
+// ToSlice - Name of the resourceLister for IAMServiceAccountKeys
+func (c *IAMServiceAccountKeys) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *IAMServiceAccountKeys) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.IAM()
+	return err
+}
+
+// List - Returns a list of all IAMServiceAccountKeys
+func (c *IAMServiceAccountKeys) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	runningAs := c.base.runningAs()
+	accountListCall := c.serviceClient.Projects.ServiceAccounts.List("projects/" + c.base.config.Project)
+	err := accountListCall.Pages(ctx, func(accountList *iam.ListServiceAccountsResponse) error {
+		for _, account := range accountList.Accounts {
+			// The key gcp-nuke authenticates with may be one of these
+			if !c.base.deletableServiceAccount(account.Email) || helpers.SliceContains(runningAs, account.Email) {
+				continue
+			}
+			keyList, err := c.serviceClient.Projects.ServiceAccounts.Keys.List(account.Name).KeyTypes("USER_MANAGED").Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			for _, key := range keyList.Keys {
+				keyID := serviceAccountID(account.Email) + "/" + keyName(key.Name)
+				if c.base.skip(c.Name(), listedItem{name: keyID, email: account.Email, created: key.ValidAfterTime}) {
+					continue
+				}
+				if runReport := report.FromContext(ctx); runReport != nil {
+					runReport.AddNote(c.Name(), keyID, keyNote(key))
+				}
+				c.resourceMap.Store(keyID, key.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *IAMServiceAccountKeys) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *IAMServiceAccountKeys) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		keyID := key.(string)
+		keyPath := value.(string)
+
+		// Parallel key deletion
+		errs.Go(keyID, "global", func(ctx context.Context) error {
+			if _, err := c.serviceClient.Projects.ServiceAccounts.Keys.Delete(keyPath).Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v]", keyID, c.Name(), c.base.config.Project)
+			c.resourceMap.Delete(keyID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
+
+// keyNote - when the key was created and when it expires, keys which never expire are valid until 9999-12-31
+func keyNote(key *iam.ServiceAccountKey) string {
+	expiry := "never expires"
+	if key.ValidBeforeTime != "" && key.ValidBeforeTime[:4] != "9999" {
+		expiry = "expires " + key.ValidBeforeTime
+	}
+	note := fmt.Sprintf("created %v, %v", key.ValidAfterTime, expiry)
+	if key.Disabled {
+		note += ", disabled"
+	}
+	return note
+}
@@ -0,0 +1,193 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/compute/metadata"
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/iam/v1"
+)
+
+// IAMServiceAccounts - service accounts created in the project, keyed by account id, deleted after their keys and the workloads running as them
+// Google-managed service accounts are never deleted, and the default compute and App Engine ones only with --include-default-service-accounts.
+// Service accounts have neither labels nor a creation time
+type IAMServiceAccounts struct {
+	serviceClient *iam.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &IAMServiceAccounts{}
+	})
+}
+
+// Name - Name of the resourceLister for IAMServiceAccounts
+func (c *IAMServiceAccounts) Name() string {
+	return "IAMServiceAccounts"
+}
+
+// ToSlice - Name of the resourceLister for IAMServiceAccounts
+func (c *IAMServiceAccounts) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *IAMServiceAccounts) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.IAM()
+	return err
+}
+
+// List - Returns a list of all IAMServiceAccounts
+func (c *IAMServiceAccounts) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	runningAs := c.base.runningAs()
+	accountListCall := c.serviceClient.Projects.ServiceAccounts.List("projects/" + c.base.config.Project)
+	err := accountListCall.Pages(ctx, func(accountList *iam.ListServiceAccountsResponse) error {
+		for _, account := range accountList.Accounts {
+			if !c.base.deletableServiceAccount(account.Email) {
+				continue
+			}
+			if helpers.SliceContains(runningAs, account.Email) {
+				log.Printf("[Skipping] Service account %v, gcp-nuke is running as it", account.Email)
+				continue
+			}
+			accountID := serviceAccountID(account.Email)
+			if c.base.skip(c.Name(), listedItem{name: accountID, email: account.Email}) {
+				continue
+			}
+			c.resourceMap.Store(accountID, account.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *IAMServiceAccounts) Dependencies() []string {
+	// Workloads lose their credentials as soon as their service account is gone, so they go first
+	a := IAMServiceAccountKeys{}
+	b := ComputeInstances{}
+	cl := ContainerGKEClusters{}
+	d := CloudRunServices{}
+	e := CloudRunJobs{}
+	f := CloudFunctions{}
+	return []string{a.Name(), b.Name(), cl.Name(), d.Name(), e.Name(), f.Name()}
+}
+
+// Remove -
+func (c *IAMServiceAccounts) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		accountID := key.(string)
+		accountName := value.(string)
+
+		// Parallel service account deletion
+		errs.Go(accountID, "global", func(ctx context.Context) error {
+			if _, err := c.serviceClient.Projects.ServiceAccounts.Delete(accountName).Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v]", accountID, c.Name(), c.base.config.Project)
+			c.resourceMap.Delete(accountID)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
+
+// deletableServiceAccount - whether the service account was created in the project, or is a default one with --include-default-service-accounts
+// Google-managed service accounts e.g. service agents are in domains of their own, and are never deleted
+func (b *ResourceBase) deletableServiceAccount(email string) bool {
+	if strings.HasSuffix(email, "@"+b.config.Project+".iam.gserviceaccount.com") {
+		return true
+	}
+	defaultAccount := strings.HasSuffix(email, "-compute@developer.gserviceaccount.com") || email == b.config.Project+"@appspot.gserviceaccount.com"
+	return defaultAccount && b.config.IncludeDefaultServiceAccounts
+}
+
+// runningAs - the service accounts gcp-nuke authenticates as, the impersonated one and the one its credentials belong to
+// Credentials are found as application default credentials are: a key file, then the gcloud default credentials,
+// then the service account of the GCE, GKE or Cloud Build metadata server
+func (b *ResourceBase) runningAs() []string {
+	accounts := []string{}
+	if b.config.ImpersonateServiceAccount != "" {
+		accounts = append(accounts, b.config.ImpersonateServiceAccount)
+	}
+	if b.config.WithoutAuthentication {
+		return accounts
+	}
+	credentialsFile := b.config.CredentialsFile
+	if credentialsFile == "" {
+		credentialsFile = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if credentialsFile == "" {
+		credentialsFile = gcloudCredentialsFile()
+	}
+	content, err := ioutil.ReadFile(credentialsFile)
+	if err == nil {
+		credentials := struct {
+			Type        string `json:"type"`
+			ClientEmail string `json:"client_email"`
+		}{}
+		if err := json.Unmarshal(content, &credentials); err == nil && credentials.Type == "service_account" {
+			accounts = append(accounts, credentials.ClientEmail)
+		}
+		return accounts
+	}
+	if metadata.OnGCE() {
+		email, err := metadata.Email("default")
+		if err != nil {
+			log.Printf("[Warning] Couldn't find the service account of the metadata server, it may be deleted: %v", err)
+			return accounts
+		}
+		accounts = append(accounts, email)
+	}
+	return accounts
+}
+
+// gcloudCredentialsFile - the application default credentials written by gcloud auth application-default login
+func gcloudCredentialsFile() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "gcloud", "application_default_credentials.json")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "gcloud", "application_default_credentials.json")
+}
+
+// serviceAccountID - the account id of a service account email e.g. ci-runner@p.iam.gserviceaccount.com gives ci-runner
+func serviceAccountID(email string) string {
+	return email[:strings.Index(email, "@")]
+}
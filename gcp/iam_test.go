@@ -0,0 +1,143 @@
+package gcp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/arehmandev/gcp-nuke/gcp/gcptest"
+	"google.golang.org/api/iam/v1"
+)
+
+// seedServiceAccounts - service accounts created by a test pipeline and a deploy job, the default and google-managed ones,
+// and the one gcp-nuke runs as, each with a user-managed key
+func seedServiceAccounts(server *gcptest.Server) {
+	project := "projects/" + testProject
+	for _, email := range []string{
+		"it-3f9a-runner@" + testProject + ".iam.gserviceaccount.com",
+		"deploy@" + testProject + ".iam.gserviceaccount.com",
+		"nuke@" + testProject + ".iam.gserviceaccount.com",
+		"123456789-compute@developer.gserviceaccount.com",
+		testProject + "@appspot.gserviceaccount.com",
+		"service-123456789@gcp-sa-pubsub.iam.gserviceaccount.com",
+	} {
+		name := project + "/serviceAccounts/" + email
+		server.Add("serviceAccounts", &iam.ServiceAccount{Name: name, Email: email})
+		server.Add("serviceAccounts/"+email+"/keys", &iam.ServiceAccountKey{Name: name + "/keys/" + serviceAccountID(email) + "-user", KeyType: "USER_MANAGED", ValidAfterTime: "2026-10-01T09:00:00Z", ValidBeforeTime: "9999-12-31T23:59:59Z"})
+		server.Add("serviceAccounts/"+email+"/keys", &iam.ServiceAccountKey{Name: name + "/keys/" + serviceAccountID(email) + "-system", KeyType: "SYSTEM_MANAGED"})
+	}
+}
+
+// TestRemoveProjectIAM - service accounts created in the project are deleted after their user-managed keys, which are noted in the report,
+// whilst default, google-managed and the running service account are kept along with their keys
+func TestRemoveProjectIAM(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	seedServiceAccounts(server)
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.ImpersonateServiceAccount = "nuke@" + testProject + ".iam.gserviceaccount.com"
+
+	runReport, err := RemoveProjectReport(testConfig, clients)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, email := range []string{"it-3f9a-runner@" + testProject + ".iam.gserviceaccount.com", "deploy@" + testProject + ".iam.gserviceaccount.com"} {
+		if server.Exists("serviceAccounts/" + email) {
+			t.Errorf("expected %v to be deleted", email)
+		}
+	}
+	for _, email := range []string{"nuke@" + testProject + ".iam.gserviceaccount.com", "123456789-compute@developer.gserviceaccount.com", testProject + "@appspot.gserviceaccount.com", "service-123456789@gcp-sa-pubsub.iam.gserviceaccount.com"} {
+		if !server.Exists("serviceAccounts/"+email) || !server.Exists("serviceAccounts/"+email+"/keys/"+serviceAccountID(email)+"-user") {
+			t.Errorf("expected %v and its key to be kept", email)
+		}
+	}
+	order := deleteOrder(server)
+	runner := "serviceAccounts/it-3f9a-runner@" + testProject + ".iam.gserviceaccount.com"
+	assertDeletedBefore(t, order, "DELETE "+runner+"/keys/it-3f9a-runner-user", "DELETE "+runner)
+	if count := len(order); count != 4 {
+		t.Errorf("expected only the user-managed keys and their service accounts to be deleted, got: %v", order)
+	}
+	notes := runReport.Resource("IAMServiceAccountKeys").Notes
+	expected := map[string]string{
+		"deploy/deploy-user":                 "created 2026-10-01T09:00:00Z, never expires",
+		"it-3f9a-runner/it-3f9a-runner-user": "created 2026-10-01T09:00:00Z, never expires",
+	}
+	if !reflect.DeepEqual(notes, expected) {
+		t.Errorf("expected the keys to be noted in the report, got: %v", notes)
+	}
+}
+
+// TestRemoveProjectIAMMetadataServer - with application default credentials from the metadata server e.g. CI on GKE or Cloud Build,
+// the service account of the metadata server is kept along with its keys
+func TestRemoveProjectIAMMetadataServer(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	seedServiceAccounts(server)
+	runner := "deploy@" + testProject + ".iam.gserviceaccount.com"
+	metadataServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Metadata-Flavor", "Google")
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/service-accounts/default/email":
+			fmt.Fprint(w, runner)
+		case "/computeMetadata/v1/instance/service-accounts/default/token":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token": "token", "expires_in": 3600, "token_type": "Bearer"}`)
+		default:
+			fmt.Fprint(w, testProject)
+		}
+	}))
+	defer metadataServer.Close()
+	t.Setenv("GCE_METADATA_HOST", strings.TrimPrefix(metadataServer.URL, "http://"))
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	t.Setenv("HOME", t.TempDir())
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.WithoutAuthentication = false
+	clients = NewClients(testConfig)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if !server.Exists("serviceAccounts/"+runner) || !server.Exists("serviceAccounts/"+runner+"/keys/deploy-user") {
+		t.Errorf("expected the service account of the metadata server and its key to be kept")
+	}
+	if server.Exists("serviceAccounts/it-3f9a-runner@" + testProject + ".iam.gserviceaccount.com") {
+		t.Errorf("expected the other service accounts to be deleted")
+	}
+}
+
+// TestRemoveProjectIAMEmailRegex - with --email-regex only matching service accounts are deleted, and the default
+// compute service account only with --include-default-service-accounts
+func TestRemoveProjectIAMEmailRegex(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	seedServiceAccounts(server)
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.EmailPatterns = []*regexp.Regexp{regexp.MustCompile(`^it-[0-9a-f]{4}-`), regexp.MustCompile(`-compute@developer\.gserviceaccount\.com$`)}
+	testConfig.IncludeDefaultServiceAccounts = true
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	remaining := []string{}
+	for _, path := range server.Paths() {
+		if strings.Count(path, "/") == 1 {
+			remaining = append(remaining, path)
+		}
+	}
+	expected := []string{
+		"serviceAccounts/deploy@" + testProject + ".iam.gserviceaccount.com",
+		"serviceAccounts/nuke@" + testProject + ".iam.gserviceaccount.com",
+		"serviceAccounts/service-123456789@gcp-sa-pubsub.iam.gserviceaccount.com",
+		"serviceAccounts/" + testProject + "@appspot.gserviceaccount.com",
+	}
+	if !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected the non matching service accounts to be kept, got: %v", remaining)
+	}
+}
@@ -3,11 +3,11 @@ package gcp
 import (
 	"context"
 	"log"
+	"net/http"
 	"strings"
 
 	"github.com/arehmandev/gcp-nuke/config"
-	"golang.org/x/oauth2/google"
-	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
 )
 
 // ResourceBase -
@@ -15,6 +15,23 @@ type ResourceBase struct {
 	config config.Config
 }
 
+// apiDisabled - whether listing failed as the api isn't enabled in the project, which means there's nothing to list
+// Many projects only use a few APIs, so the resource type is skipped rather than failing the run
+func (b *ResourceBase) apiDisabled(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	if !ok || apiErr.Code != http.StatusForbidden {
+		return false
+	}
+	disabled := strings.Contains(apiErr.Error(), "SERVICE_DISABLED")
+	for _, item := range apiErr.Errors {
+		disabled = disabled || item.Reason == "accessNotConfigured"
+	}
+	if disabled {
+		log.Printf("[Skipping] API disabled in project %v: %v", b.config.Project, apiErr.Message)
+	}
+	return disabled
+}
+
 // DefaultResourceProperties -
 type DefaultResourceProperties struct {
 	project string
@@ -22,52 +39,70 @@ type DefaultResourceProperties struct {
 	region  string
 }
 
+// regionalKey - resource map key of a type with both global and regional items, region/name for regional items
+// so items with the same name in different regions don't collide
+func regionalKey(region, name string) string {
+	if region == "" {
+		return name
+	}
+	return region + "/" + name
+}
+
+// keyName - the item name of a regionalKey
+func keyName(key string) string {
+	return key[strings.LastIndex(key, "/")+1:]
+}
+
 // Resource -
 type Resource interface {
 	Name() string
 	ToSlice() []string
-	Setup(config config.Config)
-	List(useCache bool) []string
+	Setup(config config.Config, clients *Clients) error
+	List(ctx context.Context, useCache bool) ([]string, error)
 	Dependencies() []string
-	Remove() error
+	Remove(ctx context.Context) error
 }
 
 // Ctx = context
 var Ctx = context.Background()
-var resourceMap = make(map[string]Resource)
 
-func register(resource Resource) {
-	_, exists := resourceMap[resource.Name()]
+// resourceFactories - registered resource types, instances are only created once the config is parsed
+var resourceFactories = make(map[string]func() Resource)
+
+func register(newResource func() Resource) {
+	name := newResource().Name()
+	_, exists := resourceFactories[name]
 	if exists {
-		log.Fatalf("a resource with the name %s already exists", resource.Name())
+		log.Fatalf("a resource with the name %s already exists", name)
 	}
-	resourceMap[resource.Name()] = resource
+	resourceFactories[name] = newResource
 }
 
-// GetResourceMap -
-func GetResourceMap(config config.Config) map[string]Resource {
-	for _, resource := range resourceMap {
-		resource.Setup(config)
+// GetResourceMap - new instances of every registered resource type, set up with the config and shared clients
+func GetResourceMap(config config.Config, clients *Clients) (map[string]Resource, error) {
+	resourceMap := make(map[string]Resource)
+	for name, newResource := range resourceFactories {
+		resource := newResource()
+		if err := resource.Setup(config, clients); err != nil {
+			return nil, err
+		}
+		resourceMap[name] = resource
 	}
 
-	return resourceMap
+	return resourceMap, nil
 }
 
 // GetZones -
-func GetZones(defaultContext context.Context, project string) []string {
+func GetZones(clients *Clients, project string) ([]string, error) {
 	log.Println("[Info] Retrieving zones for project:", project)
-	client, err := google.DefaultClient(defaultContext, compute.ComputeScope)
+	serviceClient, err := clients.Compute()
 	if err != nil {
-		log.Fatal(err)
-	}
-	serviceClient, err := compute.New(client)
-	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	zoneListCall := serviceClient.Zones.List(project)
 	zoneList, err := zoneListCall.Do()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	zoneStringSlice := []string{}
@@ -75,24 +110,20 @@ func GetZones(defaultContext context.Context, project string) []string {
 		zoneNameSplit := strings.Split(zone.Name, "/")
 		zoneStringSlice = append(zoneStringSlice, zoneNameSplit[len(zoneNameSplit)-1])
 	}
-	return zoneStringSlice
+	return zoneStringSlice, nil
 }
 
 // GetRegions -
-func GetRegions(defaultContext context.Context, project string) []string {
+func GetRegions(clients *Clients, project string) ([]string, error) {
 	log.Println("[Info] Retrieving regions for project:", project)
-	client, err := google.DefaultClient(defaultContext, compute.ComputeScope)
-	if err != nil {
-		log.Fatal(err)
-	}
-	serviceClient, err := compute.New(client)
+	serviceClient, err := clients.Compute()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	regionListCall := serviceClient.Regions.List(project)
 	regionList, err := regionListCall.Do()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 
 	regionStringSlice := []string{}
@@ -100,7 +131,7 @@ func GetRegions(defaultContext context.Context, project string) []string {
 		regionNameSplit := strings.Split(region.Name, "/")
 		regionStringSlice = append(regionStringSlice, regionNameSplit[len(regionNameSplit)-1])
 	}
-	return regionStringSlice
+	return regionStringSlice, nil
 }
 
 func extractGKESelfLink(input string) string {
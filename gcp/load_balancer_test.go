@@ -0,0 +1,95 @@
+package gcp
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+)
+
+// TestRemoveProjectLoadBalancers - an external http load balancer, an internal tcp load balancer, a tcp proxy and a network
+// load balancer are removed part by part, each part only once nothing uses it so no deletion has to be retried
+func TestRemoveProjectLoadBalancers(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	zone := "zones/" + testZone
+	region := "regions/" + testRegion
+
+	// External http(s) load balancer
+	server.Add("global/healthChecks", &compute.HealthCheck{Name: "web-hc"})
+	server.Add(zone+"/instanceGroupManagers", &compute.InstanceGroupManager{
+		Name:                "web",
+		AutoHealingPolicies: []*compute.InstanceGroupManagerAutoHealingPolicy{{HealthCheck: server.SelfLink("global/healthChecks/web-hc")}},
+	})
+	server.Add("global/backendServices", &compute.BackendService{
+		Name:         "web",
+		Backends:     []*compute.Backend{{Group: server.SelfLink(zone + "/instanceGroups/web")}},
+		HealthChecks: []string{server.SelfLink("global/healthChecks/web-hc")},
+	})
+	server.Add("global/backendBuckets", &compute.BackendBucket{Name: "static", BucketName: "static-assets"})
+	server.Add("global/urlMaps", &compute.UrlMap{
+		Name:           "web",
+		DefaultService: server.SelfLink("global/backendServices/web"),
+		PathMatchers:   []*compute.PathMatcher{{Name: "static", DefaultService: server.SelfLink("global/backendBuckets/static")}},
+	})
+	server.Add("global/targetHttpProxies", &compute.TargetHttpProxy{Name: "web", UrlMap: server.SelfLink("global/urlMaps/web")})
+	server.Add("global/targetHttpsProxies", &compute.TargetHttpsProxy{Name: "web", UrlMap: server.SelfLink("global/urlMaps/web")})
+	server.Add("global/forwardingRules", &compute.ForwardingRule{Name: "web-http", Target: server.SelfLink("global/targetHttpProxies/web")})
+	server.Add("global/forwardingRules", &compute.ForwardingRule{Name: "web-https", Target: server.SelfLink("global/targetHttpsProxies/web")})
+
+	// Tcp proxy in front of the same backend service
+	server.Add("global/targetTcpProxies", &compute.TargetTcpProxy{Name: "tcp", Service: server.SelfLink("global/backendServices/web")})
+	server.Add("global/forwardingRules", &compute.ForwardingRule{Name: "tcp", Target: server.SelfLink("global/targetTcpProxies/tcp")})
+
+	// Internal tcp load balancer, with the same names as the global parts
+	server.Add(region+"/healthChecks", &compute.HealthCheck{Name: "web-hc"})
+	server.Add(region+"/backendServices", &compute.BackendService{Name: "web", HealthChecks: []string{server.SelfLink(region + "/healthChecks/web-hc")}})
+	server.Add(region+"/forwardingRules", &compute.ForwardingRule{Name: "internal", BackendService: server.SelfLink(region + "/backendServices/web")})
+
+	// Network load balancer with a legacy health check
+	server.Add("global/httpHealthChecks", &compute.HttpHealthCheck{Name: "pool-hc"})
+	server.Add(region+"/targetPools", &compute.TargetPool{Name: "pool", HealthChecks: []string{server.SelfLink("global/httpHealthChecks/pool-hc")}})
+	server.Add(region+"/forwardingRules", &compute.ForwardingRule{Name: "network", Target: server.SelfLink(region + "/targetPools/pool")})
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := server.Paths(); len(remaining) != 0 {
+		t.Errorf("expected every load balancer part to be deleted, remaining: %v", remaining)
+	}
+	order := deleteOrder(server)
+	assertDeletedOnce(t, order)
+	for _, before := range [][]string{
+		{"DELETE global/forwardingRules/web-http", "DELETE global/targetHttpProxies/web"},
+		{"DELETE global/targetHttpsProxies/web", "DELETE global/urlMaps/web"},
+		{"DELETE global/urlMaps/web", "DELETE global/backendServices/web"},
+		{"DELETE global/urlMaps/web", "DELETE global/backendBuckets/static"},
+		{"DELETE global/targetTcpProxies/tcp", "DELETE global/backendServices/web"},
+		{"DELETE global/backendServices/web", "DELETE " + zone + "/instanceGroupManagers/web"},
+		{"DELETE " + zone + "/instanceGroupManagers/web", "DELETE global/healthChecks/web-hc"},
+		{"DELETE " + region + "/forwardingRules/internal", "DELETE " + region + "/backendServices/web"},
+		{"DELETE " + region + "/backendServices/web", "DELETE " + region + "/healthChecks/web-hc"},
+		{"DELETE " + region + "/targetPools/pool", "DELETE global/httpHealthChecks/pool-hc"},
+	} {
+		assertDeletedBefore(t, order, before[0], before[1])
+	}
+	if expected := []string{"DELETE global/backendServices/web", "DELETE " + region + "/backendServices/web"}; !reflect.DeepEqual(inCollection(order, "backendServices"), expected) {
+		t.Errorf("expected the global and regional backend services of the same name to both be deleted, got: %v", order)
+	}
+}
+
+// inCollection - the sorted paths in the collection, global or regional
+func inCollection(values []string, collection string) []string {
+	filtered := []string{}
+	for _, value := range values {
+		if strings.Contains(value, "/"+collection+"/") {
+			filtered = append(filtered, value)
+		}
+	}
+	sort.Strings(filtered)
+	return filtered
+}
@@ -0,0 +1,147 @@
+package gcp
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Metrics - registry of every gcp-nuke metric, served by --metrics-addr and pushed by --pushgateway-url
+var Metrics = prometheus.NewRegistry()
+
+var (
+	resourcesListed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gcp_nuke_resources_listed_total",
+		Help: "Resources found to delete, per resource type.",
+	}, []string{"type"})
+	resourcesDeleted = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gcp_nuke_resources_deleted_total",
+		Help: "Resources deleted, per resource type.",
+	}, []string{"type"})
+	resourcesFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gcp_nuke_resources_failed_total",
+		Help: "Failed resource deletion attempts, including attempts which were retried, per resource type.",
+	}, []string{"type"})
+	apiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gcp_nuke_api_requests_total",
+		Help: "GCP API requests, per api and method e.g. disks.delete.",
+	}, []string{"api", "method"})
+	apiErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "gcp_nuke_api_errors_total",
+		Help: "GCP API requests which failed or returned an error status, per api and method.",
+	}, []string{"api", "method"})
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gcp_nuke_operation_duration_seconds",
+		Help:    "Time from a delete request until its operation is done, per resource type.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"type"})
+	dependencyWait = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "gcp_nuke_dependency_wait_seconds",
+		Help:    "Time a resource type spent waiting for its dependencies to delete, per resource type.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"type"})
+)
+
+func init() {
+	Metrics.MustRegister(resourcesListed, resourcesDeleted, resourcesFailed, apiRequests, apiErrors, operationDuration, dependencyWait)
+}
+
+// MetricsHandler - serves the metrics in the prometheus text format
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(Metrics, promhttp.HandlerOpts{})
+}
+
+// ServeMetrics - serves the metrics on /metrics at addr e.g. :9090, only returns on error
+func ServeMetrics(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", MetricsHandler())
+	return http.ListenAndServe(addr, mux)
+}
+
+// PushMetrics - replaces the metrics of the project on the pushgateway, for one-shot runs which exit before being scraped
+func PushMetrics(url, project string) error {
+	return push.New(url, "gcp_nuke").Gatherer(Metrics).Grouping("project", project).Push()
+}
+
+// metricsTransport - counts requests and errors per api method
+type metricsTransport struct {
+	api  string
+	base http.RoundTripper
+}
+
+// RoundTrip -
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// The escaped path keeps names with slashes e.g. storage objects as one segment
+	method := apiMethod(req.Method, req.URL.EscapedPath())
+	apiRequests.WithLabelValues(t.api, method).Inc()
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode >= 400 {
+		apiErrors.WithLabelValues(t.api, method).Inc()
+	}
+	return resp, err
+}
+
+// storageCollections - the collections of the storage api, named b and o in its paths
+var storageCollections = map[string]string{"b": "buckets", "o": "objects"}
+
+// apiMethod - names the api method from the rest path, e.g. DELETE .../zones/z/disks/d is disks.delete,
+// GET .../zones/z/disks is disks.list and POST .../instances/i/setDiskAutoDelete is instances.setDiskAutoDelete
+// Storage paths have no project, DELETE /storage/v1/b/b/o/o is objects.delete
+func apiMethod(httpMethod, path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 2 && segments[0] == "storage" {
+		segments = segments[2:]
+		for i := 0; i < len(segments); i += 2 {
+			segments[i] = storageCollections[segments[i]]
+		}
+	}
+	// Everything up to the project is version prefix, and global is not a collection
+	for i, segment := range segments {
+		if segment == "projects" && i+1 < len(segments) {
+			segments = segments[i+2:]
+			break
+		}
+	}
+	collections := []string{}
+	for _, segment := range segments {
+		if segment != "global" {
+			collections = append(collections, segment)
+		}
+	}
+	if len(collections) == 0 {
+		return strings.ToLower(httpMethod)
+	}
+
+	if len(collections)%2 == 1 {
+		last := collections[len(collections)-1]
+		if httpMethod == http.MethodPost && len(collections) >= 3 {
+			return collections[len(collections)-3] + "." + last
+		}
+		if httpMethod == http.MethodGet {
+			return last + ".list"
+		}
+		return last + ".insert"
+	}
+
+	verbs := map[string]string{
+		http.MethodGet:    "get",
+		http.MethodDelete: "delete",
+		http.MethodPatch:  "patch",
+		http.MethodPut:    "update",
+		http.MethodPost:   "post",
+	}
+	verb, exists := verbs[httpMethod]
+	if !exists {
+		verb = strings.ToLower(httpMethod)
+	}
+	return collections[len(collections)-2] + "." + verb
+}
+
+// observeSince - records the seconds since start in the histogram of the resource type
+func observeSince(histogram *prometheus.HistogramVec, resourceType string, start time.Time) {
+	histogram.WithLabelValues(resourceType).Observe(time.Since(start).Seconds())
+}
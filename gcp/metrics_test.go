@@ -0,0 +1,102 @@
+package gcp
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/storage/v1"
+)
+
+func TestMetrics(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	seedProject(server)
+	// Object names with slashes are one segment of the escaped path, so they don't end up in the method label
+	server.Add("b", &storage.Bucket{Name: "metrics-logs"})
+	server.AddObject("metrics-logs", &storage.Object{Name: "2026/10/19/app.log", Generation: 1})
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	metricsServer := httptest.NewServer(MetricsHandler())
+	defer metricsServer.Close()
+	resp, err := http.Get(metricsServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		`gcp_nuke_resources_listed_total{type="ComputeDisks"}`,
+		`gcp_nuke_resources_deleted_total{type="ComputeInstances"}`,
+		`gcp_nuke_api_requests_total{api="compute",method="disks.delete"}`,
+		`gcp_nuke_api_requests_total{api="container",method="clusters.list"}`,
+		`gcp_nuke_api_requests_total{api="storage",method="objects.delete"}`,
+		`gcp_nuke_operation_duration_seconds_count{type="ComputeFirewalls"}`,
+		`gcp_nuke_dependency_wait_seconds_count{type="ComputeNetworks"}`,
+	} {
+		if !strings.Contains(string(body), expected) {
+			t.Errorf("expected %v in the metrics:\n%s", expected, body)
+		}
+	}
+	if strings.Contains(string(body), "app.log") {
+		t.Errorf("expected object names to be left out of the method label:\n%s", body)
+	}
+}
+
+func TestPushMetrics(t *testing.T) {
+	var pushed []string
+	pushgateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if len(body) > 0 {
+			pushed = append(pushed, r.Method+" "+r.URL.Path)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer pushgateway.Close()
+	// At least one series, so the push has a body even when this test runs alone
+	resourcesListed.WithLabelValues("ComputeDisks").Add(0)
+
+	if err := PushMetrics(pushgateway.URL, testProject); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "PUT /metrics/job/gcp_nuke/project/" + testProject
+	if len(pushed) != 1 || pushed[0] != expected {
+		t.Errorf("expected %v, got: %v", expected, pushed)
+	}
+}
+
+func TestApiMethod(t *testing.T) {
+	tests := []struct {
+		httpMethod, path, expected string
+	}{
+		{"GET", "/compute/v1/projects/p/zones", "zones.list"},
+		{"GET", "/compute/v1/projects/p/zones/z/disks", "disks.list"},
+		{"DELETE", "/compute/v1/projects/p/zones/z/disks/d", "disks.delete"},
+		{"GET", "/compute/v1/projects/p/zones/z/operations/op", "operations.get"},
+		{"POST", "/compute/v1/projects/p/zones/z/instances/vm/setDiskAutoDelete", "instances.setDiskAutoDelete"},
+		{"POST", "/compute/v1/projects/p/global/networks/vpc/removePeering", "networks.removePeering"},
+		{"DELETE", "/compute/v1/projects/p/global/firewalls/fw", "firewalls.delete"},
+		{"GET", "/v1/projects/p/locations/-/clusters", "clusters.list"},
+		{"GET", "/v1/projects/p/locations/l/clusters/c/nodePools", "nodePools.list"},
+		{"GET", "/storage/v1/b", "buckets.list"},
+		{"DELETE", "/storage/v1/b/logs", "buckets.delete"},
+		{"GET", "/storage/v1/b/logs/o", "objects.list"},
+		{"DELETE", "/storage/v1/b/logs/o/2026%2F10%2F19%2Fapp.log", "objects.delete"},
+	}
+	for _, test := range tests {
+		if method := apiMethod(test.httpMethod, test.path); method != test.expected {
+			t.Errorf("apiMethod(%v, %v) = %v, expected %v", test.httpMethod, test.path, method, test.expected)
+		}
+	}
+}
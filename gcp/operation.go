@@ -0,0 +1,101 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/compute/v1"
+)
+
+// waitForOperation - polls getStatus until the operation is DONE, or the deletion context deadline passes
+func (b *ResourceBase) waitForOperation(ctx context.Context, resourceName, description string, getStatus func() (string, error)) error {
+	return b.waitFor(ctx, resourceName, "deleted", description, getStatus)
+}
+
+// waitForComputeOperation - waitForOperation for a compute operation, regional when the region is set and global otherwise
+func (b *ResourceBase) waitForComputeOperation(ctx context.Context, serviceClient *compute.Service, resourceName, region, operationName, description string) error {
+	return b.waitForOperation(ctx, resourceName, description, func() (string, error) {
+		var checkOpp *compute.Operation
+		var err error
+		if region == "" {
+			checkOpp, err = serviceClient.GlobalOperations.Get(b.config.Project, operationName).Context(ctx).Do()
+		} else {
+			checkOpp, err = serviceClient.RegionOperations.Get(b.config.Project, region, operationName).Context(ctx).Do()
+		}
+		if err != nil {
+			return "", err
+		}
+		return checkOpp.Status, nil
+	})
+}
+
+// longRunningOperation - the json of a google.longrunning.Operation, every api using them e.g. cloud run, cloud functions
+// and serverless vpc access generates its own type for it
+type longRunningOperation struct {
+	Done  bool `json:"done"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// longRunningOperationStatus - status of a google.longrunning.Operation for waitForOperation, DONE once it is done, an operation which finished with an error fails
+// getOperation returns the operation of any api using them
+func longRunningOperationStatus(operationName string, getOperation func() (interface{}, error)) func() (string, error) {
+	return func() (string, error) {
+		checkOpp, err := getOperation()
+		if err != nil {
+			return "", err
+		}
+		content, err := json.Marshal(checkOpp)
+		if err != nil {
+			return "", err
+		}
+		operation := longRunningOperation{}
+		if err := json.Unmarshal(content, &operation); err != nil {
+			return "", err
+		}
+		if !operation.Done {
+			return "RUNNING", nil
+		}
+		if operation.Error != nil {
+			return "DONE", fmt.Errorf("operation %v failed: %v", operationName, operation.Error.Message)
+		}
+		return "DONE", nil
+	}
+}
+
+// waitFor - waitForOperation for operations other than deletions, action says what the operation does in the logs e.g. snapshotted
+func (b *ResourceBase) waitFor(ctx context.Context, resourceName, action, description string, getStatus func() (string, error)) error {
+	pollTime := b.config.PollTimeFor(resourceName)
+	start := time.Now()
+
+	for {
+		elapsed := time.Since(start).Round(time.Second)
+		log.Printf("[Info] Resource currently being %v %v (%v)", action, description, elapsed)
+
+		_, pollSpan := tracer().Start(ctx, "Poll operation", trace.WithAttributes(typeKey.String(resourceName)))
+		opStatus, err := getStatus()
+		pollSpan.SetAttributes(statusKey.String(opStatus))
+		endSpan(pollSpan, err)
+		if ctx.Err() != nil {
+			return fmt.Errorf("[Error] Resource timed out whilst being %v %v (%v)", action, description, time.Since(start).Round(time.Second))
+		}
+		if err != nil {
+			return err
+		}
+		if opStatus == "DONE" {
+			observeSince(operationDuration, resourceName, start)
+			log.Printf("[Info] Resource %v %v (%v)", action, description, time.Since(start).Round(time.Second))
+			return nil
+		}
+
+		if err := helpers.SleepContext(ctx, pollTime); err != nil {
+			return fmt.Errorf("[Error] Resource timed out whilst being %v %v (%v)", action, description, time.Since(start).Round(time.Second))
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gcp
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/pubsub/v1"
+)
+
+// PubSubSchemas - schemas are deleted once the topics validating messages against them are gone
+type PubSubSchemas struct {
+	serviceClient *pubsub.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &PubSubSchemas{}
+	})
+}
+
+// Name - Name of the resourceLister for PubSubSchemas
+func (c *PubSubSchemas) Name() string {
+	return "PubSubSchemas"
+}
+
+// ToSlice - Name of the resourceLister for PubSubSchemas
+func (c *PubSubSchemas) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *PubSubSchemas) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.PubSub()
+	return err
+}
+
+// List - Returns a list of all PubSubSchemas
+func (c *PubSubSchemas) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	schemaListCall := c.serviceClient.Projects.Schemas.List("projects/" + c.base.config.Project)
+	err := schemaListCall.Pages(ctx, func(schemaList *pubsub.ListSchemasResponse) error {
+		for _, schema := range schemaList.Schemas {
+			schemaName := pubsubName(schema.Name)
+			if c.base.skip(c.Name(), listedItem{name: schemaName}) {
+				continue
+			}
+			c.resourceMap.Store(schemaName, schema.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *PubSubSchemas) Dependencies() []string {
+	a := PubSubTopics{}
+	return []string{a.Name()}
+}
+
+// Remove -
+func (c *PubSubSchemas) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		schemaName := key.(string)
+		schemaPath := value.(string)
+
+		// Parallel schema deletion
+		errs.Go(schemaName, "global", func(ctx context.Context) error {
+			if _, err := c.serviceClient.Projects.Schemas.Delete(schemaPath).Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v]", schemaName, c.Name(), c.base.config.Project)
+			c.resourceMap.Delete(schemaName)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -0,0 +1,102 @@
+package gcp
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/pubsub/v1"
+)
+
+// PubSubSnapshots - snapshots of subscriptions, deleted before their topics
+type PubSubSnapshots struct {
+	serviceClient *pubsub.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &PubSubSnapshots{}
+	})
+}
+
+// Name - Name of the resourceLister for PubSubSnapshots
+func (c *PubSubSnapshots) Name() string {
+	return "PubSubSnapshots"
+}
+
+// ToSlice - Name of the resourceLister for PubSubSnapshots
+func (c *PubSubSnapshots) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *PubSubSnapshots) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.PubSub()
+	return err
+}
+
+// List - Returns a list of all PubSubSnapshots
+func (c *PubSubSnapshots) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	snapshotListCall := c.serviceClient.Projects.Snapshots.List("projects/" + c.base.config.Project)
+	err := snapshotListCall.Pages(ctx, func(snapshotList *pubsub.ListSnapshotsResponse) error {
+		for _, snapshot := range snapshotList.Snapshots {
+			snapshotName := pubsubName(snapshot.Name)
+			if c.base.skip(c.Name(), listedItem{name: snapshotName, labels: snapshot.Labels}) {
+				continue
+			}
+			c.resourceMap.Store(snapshotName, snapshot.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *PubSubSnapshots) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *PubSubSnapshots) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		snapshotName := key.(string)
+		snapshotPath := value.(string)
+
+		// Parallel snapshot deletion
+		errs.Go(snapshotName, "global", func(ctx context.Context) error {
+			if _, err := c.serviceClient.Projects.Snapshots.Delete(snapshotPath).Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v]", snapshotName, c.Name(), c.base.config.Project)
+			c.resourceMap.Delete(snapshotName)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -0,0 +1,103 @@
+package gcp
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/pubsub/v1"
+)
+
+// PubSubSubscriptions - subscriptions, including those to topics in other projects, deleted before their topics
+// Subscriptions have no creation time, so only a ttl label holding a date e.g. expires-at=2026-10-20 expires them
+type PubSubSubscriptions struct {
+	serviceClient *pubsub.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &PubSubSubscriptions{}
+	})
+}
+
+// Name - Name of the resourceLister for PubSubSubscriptions
+func (c *PubSubSubscriptions) Name() string {
+	return "PubSubSubscriptions"
+}
+
+// ToSlice - Name of the resourceLister for PubSubSubscriptions
+func (c *PubSubSubscriptions) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *PubSubSubscriptions) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.PubSub()
+	return err
+}
+
+// List - Returns a list of all PubSubSubscriptions
+func (c *PubSubSubscriptions) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	subscriptionListCall := c.serviceClient.Projects.Subscriptions.List("projects/" + c.base.config.Project)
+	err := subscriptionListCall.Pages(ctx, func(subscriptionList *pubsub.ListSubscriptionsResponse) error {
+		for _, subscription := range subscriptionList.Subscriptions {
+			subscriptionName := pubsubName(subscription.Name)
+			if c.base.skip(c.Name(), listedItem{name: subscriptionName, labels: subscription.Labels}) {
+				continue
+			}
+			c.resourceMap.Store(subscriptionName, subscription.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *PubSubSubscriptions) Dependencies() []string {
+	return []string{}
+}
+
+// Remove -
+func (c *PubSubSubscriptions) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		subscriptionName := key.(string)
+		subscriptionPath := value.(string)
+
+		// Parallel subscription deletion
+		errs.Go(subscriptionName, "global", func(ctx context.Context) error {
+			if _, err := c.serviceClient.Projects.Subscriptions.Delete(subscriptionPath).Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v]", subscriptionName, c.Name(), c.base.config.Project)
+			c.resourceMap.Delete(subscriptionName)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -0,0 +1,52 @@
+package gcp
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+
+	"google.golang.org/api/pubsub/v1"
+)
+
+// TestRemoveProjectPubSub - the randomly named leftovers of integration tests are deleted, subscriptions and snapshots before
+// their topics and topics before their schemas, whilst resources not matching --name-regex or the owner filter are kept
+func TestRemoveProjectPubSub(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	project := "projects/" + testProject
+
+	server.Add("schemas", &pubsub.Schema{Name: project + "/schemas/it-3f9a-event", Type: "AVRO"})
+	server.Add("topics", &pubsub.Topic{Name: project + "/topics/it-3f9a", SchemaSettings: &pubsub.SchemaSettings{Schema: project + "/schemas/it-3f9a-event"}})
+	server.Add("subscriptions", &pubsub.Subscription{Name: project + "/subscriptions/it-3f9a-sub", Topic: project + "/topics/it-3f9a"})
+	server.Add("snapshots", &pubsub.Snapshot{Name: project + "/snapshots/it-3f9a-snap", Topic: project + "/topics/it-3f9a"})
+	server.Add("topics", &pubsub.Topic{Name: project + "/topics/it-77c1", Labels: map[string]string{"owner": "payments"}})
+	server.Add("subscriptions", &pubsub.Subscription{Name: project + "/subscriptions/it-77c1-sub", Topic: project + "/topics/it-77c1", Labels: map[string]string{"owner": "payments"}})
+	server.Add("topics", &pubsub.Topic{Name: project + "/topics/orders"})
+	server.Add("subscriptions", &pubsub.Subscription{Name: project + "/subscriptions/orders-billing", Topic: project + "/topics/orders"})
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.NamePatterns = []*regexp.Regexp{regexp.MustCompile(`^it-[0-9a-f]{4}`)}
+	testConfig.Owners = []string{"unowned"}
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"subscriptions/it-77c1-sub",
+		"subscriptions/orders-billing",
+		"topics/it-77c1",
+		"topics/orders",
+	}
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected the owned and non matching resources to be kept, got: %v", remaining)
+	}
+	order := deleteOrder(server)
+	assertDeletedOnce(t, order)
+	for _, before := range [][]string{
+		{"DELETE subscriptions/it-3f9a-sub", "DELETE topics/it-3f9a"},
+		{"DELETE snapshots/it-3f9a-snap", "DELETE topics/it-3f9a"},
+		{"DELETE topics/it-3f9a", "DELETE schemas/it-3f9a-event"},
+	} {
+		assertDeletedBefore(t, order, before[0], before[1])
+	}
+}
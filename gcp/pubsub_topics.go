@@ -0,0 +1,111 @@
+package gcp
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/pubsub/v1"
+)
+
+// PubSubTopics - topics are deleted once their subscriptions and snapshots are gone, so none are left detached
+// Topics have no creation time, so only a ttl label holding a date e.g. expires-at=2026-10-20 expires them
+type PubSubTopics struct {
+	serviceClient *pubsub.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &PubSubTopics{}
+	})
+}
+
+// Name - Name of the resourceLister for PubSubTopics
+func (c *PubSubTopics) Name() string {
+	return "PubSubTopics"
+}
+
+// ToSlice - Name of the resourceLister for PubSubTopics
+func (c *PubSubTopics) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *PubSubTopics) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.PubSub()
+	return err
+}
+
+// List - Returns a list of all PubSubTopics
+func (c *PubSubTopics) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	topicListCall := c.serviceClient.Projects.Topics.List("projects/" + c.base.config.Project)
+	err := topicListCall.Pages(ctx, func(topicList *pubsub.ListTopicsResponse) error {
+		for _, topic := range topicList.Topics {
+			topicName := pubsubName(topic.Name)
+			if c.base.skip(c.Name(), listedItem{name: topicName, labels: topic.Labels}) {
+				continue
+			}
+			c.resourceMap.Store(topicName, topic.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *PubSubTopics) Dependencies() []string {
+	a := PubSubSubscriptions{}
+	b := PubSubSnapshots{}
+	return []string{a.Name(), b.Name()}
+}
+
+// Remove -
+func (c *PubSubTopics) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		topicName := key.(string)
+		topicPath := value.(string)
+
+		// Parallel topic deletion
+		errs.Go(topicName, "global", func(ctx context.Context) error {
+			if _, err := c.serviceClient.Projects.Topics.Delete(topicPath).Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v]", topicName, c.Name(), c.base.config.Project)
+			c.resourceMap.Delete(topicName)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
+
+// pubsubName - the last segment of a pub/sub resource name e.g. projects/p/topics/orders gives orders
+func pubsubName(resourceName string) string {
+	return resourceName[strings.LastIndex(resourceName, "/")+1:]
+}
@@ -0,0 +1,79 @@
+package gcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/gcp/cassette"
+)
+
+// replayConfig - config replaying the cassette instead of calling the real apis
+func replayConfig(t *testing.T, path string) (config.Config, *Clients, *cassette.Replayer) {
+	recorded, err := cassette.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer := cassette.NewReplayer(recorded)
+
+	testConfig := config.Config{
+		Project:               testProject,
+		Zones:                 []string{testZone},
+		Regions:               []string{testRegion},
+		Context:               context.Background(),
+		Timeout:               5 * time.Second,
+		PollTime:              10 * time.Millisecond,
+		MaxConcurrency:        1,
+		WithoutAuthentication: true,
+	}
+	clients := NewClients(testConfig)
+	clients.Transport = replayer
+	return testConfig, clients, replayer
+}
+
+// onlyResourceTypes - registers just the named resource types, so a cassette only holds the apis of its regression
+// and isn't touched by every new resource type, call the returned func to restore the rest
+func onlyResourceTypes(t *testing.T, names ...string) func() {
+	registered := resourceFactories
+	resourceFactories = make(map[string]func() Resource)
+	for _, name := range names {
+		newResource, exists := registered[name]
+		if !exists {
+			t.Fatalf("unknown resource type %v", name)
+		}
+		resourceFactories[name] = newResource
+	}
+	return func() { resourceFactories = registered }
+}
+
+// TestReplayGKEGhostInstanceGroup - instance groups left behind by a gke cluster are still listed for a while after
+// the cluster deletion, but return 404 when deleted
+func TestReplayGKEGhostInstanceGroup(t *testing.T) {
+	defer onlyResourceTypes(t,
+		"ComputeDisks",
+		"ComputeFirewalls",
+		"ComputeInstanceGroupsRegion",
+		"ComputeInstanceGroupsZone",
+		"ComputeInstanceTemplates",
+		"ComputeInstances",
+		"ComputeNetworkPeerings",
+		"ComputeNetworks",
+		"ComputeRegionAutoScalers",
+		"ComputeRouters",
+		"ComputeSubnetworks",
+		"ComputeVPNGateways",
+		"ComputeVPNTunnels",
+		"ComputeZoneAutoScalers",
+		"ContainerGKEClusters",
+	)()
+	testConfig, clients, replayer := replayConfig(t, "testdata/gke_ghost_instance_group.json")
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if unused := replayer.Unused(); len(unused) != 0 {
+		t.Errorf("expected the run to follow the recording, never replayed: %v", unused)
+	}
+}
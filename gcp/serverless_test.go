@@ -0,0 +1,136 @@
+package gcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/api/cloudfunctions/v2"
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/run/v2"
+	"google.golang.org/api/vpcaccess/v1"
+)
+
+// TestRemoveProjectServerless - services, jobs and functions of both generations are deleted once their operations are done,
+// 2nd gen functions along with their service, and before the subnetworks their VPC egress goes through
+func TestRemoveProjectServerless(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	location := "projects/" + testProject + "/locations/" + testRegion
+	regionPath := "locations/" + testRegion
+
+	server.Add("regions/"+testRegion+"/subnetworks", &compute.Subnetwork{Name: "serverless"})
+	server.Add(regionPath+"/services", &run.GoogleCloudRunV2Service{Name: location + "/services/api", Creator: "dev@example.com"})
+	server.Add(regionPath+"/services", &run.GoogleCloudRunV2Service{Name: location + "/services/resize", Labels: map[string]string{"goog-managed-by": "cloudfunctions"}})
+	server.Add(regionPath+"/jobs", &run.GoogleCloudRunV2Job{Name: location + "/jobs/nightly"})
+	server.Add(regionPath+"/functions", &cloudfunctions.Function{Name: location + "/functions/resize", Environment: "GEN_2", ServiceConfig: &cloudfunctions.ServiceConfig{Service: location + "/services/resize"}})
+	server.Add(regionPath+"/functions", &cloudfunctions.Function{Name: location + "/functions/legacy", Environment: "GEN_1"})
+	server.SlowOperation(regionPath+"/functions/resize", 3)
+	server.SlowOperation(regionPath+"/services/api", 2)
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := server.Paths(); len(remaining) != 0 {
+		t.Errorf("expected the services, jobs, functions and subnetwork to be deleted, remaining: %v", remaining)
+	}
+	if containsRequest(server, "DELETE", "/services/resize") {
+		t.Error("expected the service of the 2nd gen function to be deleted with the function")
+	}
+	order := deleteOrder(server)
+	subnetwork := "DELETE regions/" + testRegion + "/subnetworks/serverless"
+	for _, deleted := range []string{"services/api", "jobs/nightly", "functions/resize", "functions/legacy"} {
+		assertDeletedBefore(t, order, "DELETE "+regionPath+"/"+deleted, subnetwork)
+	}
+	// The last poll of each slow operation comes before the subnetwork is deleted
+	polls := 0
+	for _, request := range server.Requests() {
+		if strings.HasPrefix(request, "DELETE ") && strings.HasSuffix(request, "/subnetworks/serverless") {
+			break
+		}
+		if strings.HasPrefix(request, "GET ") && strings.Contains(request, regionPath+"/operations/") {
+			polls++
+		}
+	}
+	if polls < 5 {
+		t.Errorf("expected the slow operations to be polled until done before the subnetwork is deleted, got: %v", server.Requests())
+	}
+}
+
+// TestLongRunningOperationStatus - operations of every api using google.longrunning.Operation are running until done, and fail with their error
+func TestLongRunningOperationStatus(t *testing.T) {
+	tests := []struct {
+		operation interface{}
+		status    string
+		err       string
+	}{
+		{&run.GoogleLongrunningOperation{Name: "op"}, "RUNNING", ""},
+		{&run.GoogleLongrunningOperation{Name: "op", Done: true}, "DONE", ""},
+		{&cloudfunctions.Operation{Name: "op", Done: true, Error: &cloudfunctions.Status{Message: "function in use"}}, "DONE", "operation op failed: function in use"},
+	}
+	for _, test := range tests {
+		operation := test.operation
+		status, err := longRunningOperationStatus("op", func() (interface{}, error) { return operation, nil })()
+		if status != test.status || (err == nil) != (test.err == "") || (err != nil && err.Error() != test.err) {
+			t.Errorf("expected %v %q for %+v, got: %v %v", test.status, test.err, operation, status, err)
+		}
+	}
+}
+
+// TestRemoveProjectVPCAccessConnectors - connectors are deleted after the services using them and before their subnetwork
+// and network, so neither deletion fails as in use
+func TestRemoveProjectVPCAccessConnectors(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	location := "projects/" + testProject + "/locations/" + testRegion
+	regionPath := "locations/" + testRegion
+
+	server.Add("global/networks", &compute.Network{Name: "vpc"})
+	server.Add("regions/"+testRegion+"/subnetworks", &compute.Subnetwork{Name: "connectors", Network: server.SelfLink("global/networks/vpc")})
+	server.Add(regionPath+"/connectors", &vpcaccess.Connector{Name: location + "/connectors/from-subnet", Subnet: &vpcaccess.Subnet{Name: "connectors"}})
+	server.Add(regionPath+"/connectors", &vpcaccess.Connector{Name: location + "/connectors/from-range", Network: "vpc", IpCidrRange: "10.8.0.0/28"})
+	server.Add(regionPath+"/services", &run.GoogleCloudRunV2Service{
+		Name:     location + "/services/api",
+		Template: &run.GoogleCloudRunV2RevisionTemplate{VpcAccess: &run.GoogleCloudRunV2VpcAccess{Connector: location + "/connectors/from-range"}},
+	})
+	server.SlowOperation(regionPath+"/connectors/from-subnet", 2)
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := server.Paths(); len(remaining) != 0 {
+		t.Errorf("expected the service, connectors, subnetwork and network to be deleted, remaining: %v", remaining)
+	}
+	order := deleteOrder(server)
+	assertDeletedOnce(t, order)
+	for _, before := range [][]string{
+		{"DELETE " + regionPath + "/services/api", "DELETE " + regionPath + "/connectors/from-range"},
+		{"DELETE " + regionPath + "/connectors/from-subnet", "DELETE regions/" + testRegion + "/subnetworks/connectors"},
+		{"DELETE " + regionPath + "/connectors/from-range", "DELETE global/networks/vpc"},
+	} {
+		assertDeletedBefore(t, order, before[0], before[1])
+	}
+}
+
+// TestRemoveProjectVPCAccessConnectorsTTL - connectors have no creation time, so --ttl-label runs leave them even with a default ttl
+func TestRemoveProjectVPCAccessConnectorsTTL(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	location := "projects/" + testProject + "/locations/" + testRegion
+	server.Add("locations/"+testRegion+"/connectors", &vpcaccess.Connector{Name: location + "/connectors/from-range", Network: "vpc", IpCidrRange: "10.8.0.0/28"})
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.TTLLabels = []string{"ttl"}
+	testConfig.TTLDefault = time.Hour
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	if !server.Exists("locations/" + testRegion + "/connectors/from-range") {
+		t.Error("expected the connector to be kept")
+	}
+}
@@ -0,0 +1,125 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/arehmandev/gcp-nuke/report"
+	"google.golang.org/api/compute/v1"
+)
+
+// snapshotLabel - label on every snapshot taken by --snapshot-disks
+const snapshotLabel = "gcp-nuke-snapshot"
+
+// defaultSnapshotSkipLabel - disks with this label aren't snapshotted when --snapshot-skip-label isn't set
+const defaultSnapshotSkipLabel = "no-snapshot"
+
+// snapshotDisk - with --snapshot-disks, snapshots the disk and waits for the snapshot before the disk is deleted
+// The location of the disk is zones/<zone>, or regions/<region> for regional persistent disks
+// Disks with the --snapshot-skip-label label are deleted without a snapshot
+func (b *ResourceBase) snapshotDisk(ctx context.Context, serviceClient *compute.Service, resourceName, location, diskName string) error {
+	if !b.config.SnapshotDisks {
+		return nil
+	}
+	runReport := report.FromContext(ctx)
+	if runReport != nil {
+		if snapshot, taken := runReport.SnapshotOf(resourceName, diskName); taken {
+			log.Printf("[Skipping] Snapshot of disk %v, already snapshotted to %v", diskName, snapshot)
+			return nil
+		}
+	}
+	scope, locationName := splitDiskLocation(location)
+	regional := scope == "regions"
+
+	var disk *compute.Disk
+	var err error
+	if regional {
+		disk, err = serviceClient.RegionDisks.Get(b.config.Project, locationName, diskName).Context(ctx).Do()
+	} else {
+		disk, err = serviceClient.Disks.Get(b.config.Project, locationName, diskName).Context(ctx).Do()
+	}
+	if err != nil {
+		return err
+	}
+	skipLabel := b.config.SnapshotSkipLabel
+	if skipLabel == "" {
+		skipLabel = defaultSnapshotSkipLabel
+	}
+	if _, skip := disk.Labels[skipLabel]; skip {
+		log.Printf("[Skipping] Snapshot of disk %v, it has the label %v", diskName, skipLabel)
+		return nil
+	}
+
+	scopeName := strings.TrimSuffix(scope, "s")
+	snapshot := &compute.Snapshot{
+		Name:        snapshotName(diskName, locationName, now().UTC().Format("20060102-150405")),
+		Description: fmt.Sprintf("Snapshot of disk %v in %v %v taken by gcp-nuke before deleting it", diskName, scopeName, locationName),
+		Labels: map[string]string{
+			snapshotLabel:         "true",
+			"source-" + scopeName: locationName,
+		},
+	}
+	var operation *compute.Operation
+	if regional {
+		operation, err = serviceClient.RegionDisks.CreateSnapshot(b.config.Project, locationName, diskName, snapshot).Context(ctx).Do()
+	} else {
+		operation, err = serviceClient.Disks.CreateSnapshot(b.config.Project, locationName, diskName, snapshot).Context(ctx).Do()
+	}
+	if err != nil {
+		return fmt.Errorf("snapshot of disk %v failed, not deleting it: %v", diskName, err)
+	}
+	description := fmt.Sprintf("%v to %v [type: %v project: %v %v: %v]", diskName, snapshot.Name, resourceName, b.config.Project, scopeName, locationName)
+	err = b.waitFor(ctx, resourceName, "snapshotted", description, func() (string, error) {
+		var checkOpp *compute.Operation
+		var err error
+		if regional {
+			checkOpp, err = serviceClient.RegionOperations.Get(b.config.Project, locationName, operation.Name).Context(ctx).Do()
+		} else {
+			checkOpp, err = serviceClient.ZoneOperations.Get(b.config.Project, locationName, operation.Name).Context(ctx).Do()
+		}
+		if err != nil {
+			return "", err
+		}
+		if checkOpp.Error != nil && len(checkOpp.Error.Errors) > 0 {
+			return "", fmt.Errorf("snapshot of disk %v failed, not deleting it: %v", diskName, checkOpp.Error.Errors[0].Message)
+		}
+		return checkOpp.Status, nil
+	})
+	if err != nil {
+		return err
+	}
+	if runReport != nil {
+		runReport.AddSnapshot(resourceName, diskName, snapshot.Name)
+	}
+	return nil
+}
+
+// diskOfSource - the location and name of an attached disk from its source url, .../zones/<zone>/disks/<name> or .../regions/<region>/disks/<name>
+func diskOfSource(source string) (location, diskName string) {
+	segments := strings.Split(source, "/")
+	if len(segments) < 4 {
+		return "", source
+	}
+	return strings.Join(segments[len(segments)-4:len(segments)-2], "/"), segments[len(segments)-1]
+}
+
+// splitDiskLocation - zones/<zone> or regions/<region> into the scope and its name
+func splitDiskLocation(location string) (scope, name string) {
+	split := strings.SplitN(location, "/", 2)
+	if len(split) != 2 {
+		return "zones", location
+	}
+	return split[0], split[1]
+}
+
+// snapshotName - disk-zone-timestamp, shortening the disk name to fit the 63 character limit
+// The zone keeps snapshots of disks with the same name in different zones apart
+func snapshotName(diskName, zone, timestamp string) string {
+	suffix := "-" + zone + "-" + timestamp
+	if len(diskName)+len(suffix) > 63 {
+		diskName = strings.TrimRight(diskName[:63-len(suffix)], "-")
+	}
+	return diskName + suffix
+}
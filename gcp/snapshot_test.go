@@ -0,0 +1,93 @@
+package gcp
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/api/compute/v1"
+)
+
+func TestRemoveProjectSnapshotsDisks(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+
+	server := newTestServer()
+	defer server.Close()
+	zone := "zones/" + testZone
+	server.Add(zone+"/disks", &compute.Disk{Name: "scratch"})
+	server.Add(zone+"/disks", &compute.Disk{Name: "cache", Labels: map[string]string{"no-snapshot": ""}})
+	server.Add(zone+"/disks", &compute.Disk{Name: "boot", Users: []string{server.SelfLink(zone + "/instances/vm")}})
+	server.Add(zone+"/instances", &compute.Instance{
+		Name:     "vm",
+		Metadata: &compute.Metadata{},
+		Disks: []*compute.AttachedDisk{
+			{DeviceName: "boot", Type: "PERSISTENT", Source: server.SelfLink(zone + "/disks/boot")},
+			{DeviceName: "local-ssd", Type: "SCRATCH"},
+		},
+	})
+	// The retried deletion mustn't take a second snapshot
+	server.InUse(zone+"/disks/scratch", 1)
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.SnapshotDisks = true
+
+	runReport, err := RemoveProjectReport(testConfig, clients)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"global/snapshots/boot-europe-west1-b-20261019-120000",
+		"global/snapshots/scratch-europe-west1-b-20261019-120000",
+	}
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected every disk deleted and only the snapshots left, got: %v", remaining)
+	}
+	if snapshots := runReport.Resource("ComputeDisks").Snapshots; !reflect.DeepEqual(snapshots, map[string]string{"scratch": "scratch-europe-west1-b-20261019-120000"}) {
+		t.Errorf("expected the disk snapshot in the report, got: %v", snapshots)
+	}
+	if snapshots := runReport.Resource("ComputeInstances").Snapshots; !reflect.DeepEqual(snapshots, map[string]string{"boot": "boot-europe-west1-b-20261019-120000"}) {
+		t.Errorf("expected the boot disk snapshot in the report, got: %v", snapshots)
+	}
+}
+
+// TestRemoveProjectSnapshotsRegionalDisks - a regional disk attached to an instance is snapshotted in its region, not the zone of the instance
+func TestRemoveProjectSnapshotsRegionalDisks(t *testing.T) {
+	defer func() { now = time.Now }()
+	now = func() time.Time { return time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC) }
+
+	server := newTestServer()
+	defer server.Close()
+	zone := "zones/" + testZone
+	region := "regions/" + testRegion
+	server.Add(region+"/disks", &compute.Disk{Name: "shared", Users: []string{server.SelfLink(zone + "/instances/vm")}})
+	server.Add(zone+"/instances", &compute.Instance{
+		Name:     "vm",
+		Metadata: &compute.Metadata{},
+		Disks:    []*compute.AttachedDisk{{DeviceName: "shared", Type: "PERSISTENT", Source: server.SelfLink(region + "/disks/shared")}},
+	})
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.SnapshotDisks = true
+
+	runReport, err := RemoveProjectReport(testConfig, clients)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if server.Exists(zone + "/instances/vm") {
+		t.Error("expected the instance to be deleted")
+	}
+	if !server.Exists("global/snapshots/shared-" + testRegion + "-20261019-120000") {
+		t.Errorf("expected a snapshot of the regional disk, got: %v", server.Paths())
+	}
+	if snapshots := runReport.Resource("ComputeInstances").Snapshots; !reflect.DeepEqual(snapshots, map[string]string{"shared": "shared-" + testRegion + "-20261019-120000"}) {
+		t.Errorf("expected the regional disk snapshot in the report, got: %v", snapshots)
+	}
+}
+
+func TestSnapshotName(t *testing.T) {
+	name := snapshotName("a-very-long-disk-name-which-goes-on-and-on-and-on", "northamerica-northeast1-a", "20261019-120000")
+	if len(name) > 63 || name != "a-very-long-disk-name-northamerica-northeast1-a-20261019-120000" {
+		t.Errorf("expected the disk name shortened to fit, got: %v (%v)", name, len(name))
+	}
+}
@@ -0,0 +1,262 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"github.com/arehmandev/gcp-nuke/report"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/sqladmin/v1beta4"
+)
+
+// sqlNameReuseDelay - how long the name of a deleted Cloud SQL instance can't be reused for
+const sqlNameReuseDelay = 7 * 24 * time.Hour
+
+// SQLInstances - Cloud SQL instances, read replicas are deleted before their primaries
+// Instances with a private IP hold the service networking peering of their network until they are gone
+type SQLInstances struct {
+	serviceClient *sqladmin.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+// sqlInstanceProperties -
+type sqlInstanceProperties struct {
+	region             string
+	replicas           []string
+	deletionProtection bool
+}
+
+func init() {
+	register(func() Resource {
+		return &SQLInstances{}
+	})
+}
+
+// Name - Name of the resourceLister for SQLInstances
+func (c *SQLInstances) Name() string {
+	return "SQLInstances"
+}
+
+// ToSlice - Name of the resourceLister for SQLInstances
+func (c *SQLInstances) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *SQLInstances) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.SQLAdmin()
+	return err
+}
+
+// List - Returns a list of all SQLInstances
+func (c *SQLInstances) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	instanceListCall := c.serviceClient.Instances.List(c.base.config.Project)
+	err := instanceListCall.Pages(ctx, func(instanceList *sqladmin.InstancesListResponse) error {
+		for _, instance := range instanceList.Items {
+			instanceResource := sqlInstanceProperties{
+				region:   instance.Region,
+				replicas: instance.ReplicaNames,
+			}
+			var labels map[string]string
+			if instance.Settings != nil {
+				labels = instance.Settings.UserLabels
+				instanceResource.deletionProtection = instance.Settings.DeletionProtectionEnabled
+			}
+			if c.base.skip(c.Name(), listedItem{name: instance.Name, labels: labels, created: instance.CreateTime}) {
+				continue
+			}
+			if instanceResource.deletionProtection && !c.base.config.DisableDeletionProtection {
+				log.Printf("[Warning] SQL instance %v has deletion protection enabled, it can only be deleted with --disable-deletion-protection", instance.Name)
+			}
+			c.resourceMap.Store(instance.Name, instanceResource)
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	c.skipPrimariesOfKeptReplicas()
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *SQLInstances) Dependencies() []string {
+	return []string{}
+}
+
+// Remove - deletes the instances a level of replication at a time, replicas of replicas first and primaries last
+// A primary is left when any of its replicas couldn't be deleted, as Cloud SQL refuses to delete it
+func (c *SQLInstances) Remove(ctx context.Context) error {
+	var firstErr error
+	for _, level := range c.replicationLevels() {
+
+		// Removal logic
+		errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+		for _, instanceName := range level {
+			value, exists := c.resourceMap.Load(instanceName)
+			if !exists {
+				continue
+			}
+			instanceName := instanceName
+			instanceResource := value.(sqlInstanceProperties)
+			if remaining := c.remainingReplicas(instanceResource); len(remaining) > 0 {
+				log.Printf("[Skipping] SQL instance %v, its replicas %v are still there", instanceName, remaining)
+				continue
+			}
+
+			// Parallel instance deletion
+			errs.Go(instanceName, instanceResource.region, func(ctx context.Context) error {
+				description := fmt.Sprintf("%v [type: %v project: %v region: %v]", instanceName, c.Name(), c.base.config.Project, instanceResource.region)
+				if instanceResource.deletionProtection {
+					if err := c.disableDeletionProtection(ctx, instanceName, description); err != nil {
+						return err
+					}
+				}
+
+				operation, err := c.serviceClient.Instances.Delete(c.base.config.Project, instanceName).Context(ctx).Do()
+				if err != nil {
+					return err
+				}
+				err = c.base.waitForOperation(ctx, c.Name(), description, c.operationStatus(ctx, operation.Name))
+				if err != nil {
+					return err
+				}
+				reusable := now().Add(sqlNameReuseDelay).UTC().Format("2006-01-02")
+				log.Printf("[Info] The name of SQL instance %v can't be reused until %v", instanceName, reusable)
+				if runReport := report.FromContext(ctx); runReport != nil {
+					runReport.AddNote(c.Name(), instanceName, "name can't be reused until "+reusable)
+				}
+				c.resourceMap.Delete(instanceName)
+				return nil
+			})
+		}
+		// Wait for the level to complete, the next level holds the primaries of these replicas
+		if err := errs.Wait(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// disableDeletionProtection - turns off deletion protection with --disable-deletion-protection, otherwise the instance is left
+func (c *SQLInstances) disableDeletionProtection(ctx context.Context, instanceName, description string) error {
+	if !c.base.config.DisableDeletionProtection {
+		// Deletion protection is there on purpose, so leaving the instance isn't an error
+		log.Printf("[Warning] SQL instance %v has deletion protection enabled, rerun with --disable-deletion-protection to delete it", instanceName)
+		if runReport := report.FromContext(ctx); runReport != nil {
+			runReport.AddNote(c.Name(), instanceName, "deletion protection enabled")
+		}
+		return errLeftInPlace
+	}
+	patch := &sqladmin.DatabaseInstance{
+		Settings: &sqladmin.Settings{
+			DeletionProtectionEnabled: false,
+			ForceSendFields:           []string{"DeletionProtectionEnabled"},
+		},
+	}
+	operation, err := c.serviceClient.Instances.Patch(c.base.config.Project, instanceName, patch).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("turning off deletion protection of SQL instance %v: %v", instanceName, err)
+	}
+	return c.base.waitFor(ctx, c.Name(), "unprotected", description, c.operationStatus(ctx, operation.Name))
+}
+
+// operationStatus - status of a sqladmin operation, an operation which finished with errors fails
+func (c *SQLInstances) operationStatus(ctx context.Context, operationName string) func() (string, error) {
+	return func() (string, error) {
+		checkOpp, err := c.serviceClient.Operations.Get(c.base.config.Project, operationName).Context(ctx).Do()
+		if err != nil {
+			return "", err
+		}
+		if checkOpp.Error != nil && len(checkOpp.Error.Errors) > 0 {
+			messages := []string{}
+			for _, operationError := range checkOpp.Error.Errors {
+				messages = append(messages, fmt.Sprintf("%v: %v", operationError.Code, operationError.Message))
+			}
+			return checkOpp.Status, fmt.Errorf("operation %v failed: %v", operationName, strings.Join(messages, ", "))
+		}
+		return checkOpp.Status, nil
+	}
+}
+
+// replicationLevels - the listed instances grouped by how many levels of replicas they have below them, leaves first
+func (c *SQLInstances) replicationLevels() [][]string {
+	depths := make(map[string]int)
+	var depth func(instanceName string) int
+	depth = func(instanceName string) int {
+		if known, exists := depths[instanceName]; exists {
+			return known
+		}
+		deepest := 0
+		if value, exists := c.resourceMap.Load(instanceName); exists {
+			for _, replica := range value.(sqlInstanceProperties).replicas {
+				if _, listed := c.resourceMap.Load(replica); listed {
+					if replicaDepth := depth(replica) + 1; replicaDepth > deepest {
+						deepest = replicaDepth
+					}
+				}
+			}
+		}
+		depths[instanceName] = deepest
+		return deepest
+	}
+
+	levels := [][]string{}
+	for _, instanceName := range c.ToSlice() {
+		level := depth(instanceName)
+		for len(levels) <= level {
+			levels = append(levels, []string{})
+		}
+		levels[level] = append(levels[level], instanceName)
+	}
+	return levels
+}
+
+// skipPrimariesOfKeptReplicas - leaves the primaries of replicas kept by the filters, as Cloud SQL refuses to delete a primary with replicas
+// Repeated until nothing changes, so a replica of a replica kept by the filters keeps the whole chain above it
+func (c *SQLInstances) skipPrimariesOfKeptReplicas() {
+	for skipped := true; skipped; {
+		skipped = false
+		for _, instanceName := range c.ToSlice() {
+			value, _ := c.resourceMap.Load(instanceName)
+			for _, replica := range value.(sqlInstanceProperties).replicas {
+				if _, listed := c.resourceMap.Load(replica); !listed {
+					log.Printf("[Skipping] SQL instance %v, its replica %v is kept", instanceName, replica)
+					c.resourceMap.Delete(instanceName)
+					skipped = true
+					break
+				}
+			}
+		}
+	}
+}
+
+// remainingReplicas - replicas of the instance listed for deletion which haven't been deleted yet
+func (c *SQLInstances) remainingReplicas(instanceResource sqlInstanceProperties) []string {
+	remaining := []string{}
+	for _, replica := range instanceResource.replicas {
+		if _, listed := c.resourceMap.Load(replica); listed {
+			remaining = append(remaining, replica)
+		}
+	}
+	return remaining
+}
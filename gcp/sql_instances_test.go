@@ -0,0 +1,154 @@
+package gcp
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"google.golang.org/api/compute/v1"
+	"google.golang.org/api/sqladmin/v1beta4"
+)
+
+// TestRemoveProjectSQLInstances - replicas go before their primaries, and private IP instances before the peering to the service producer
+func TestRemoveProjectSQLInstances(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	network := server.SelfLink("global/networks/vpc")
+
+	server.Add("global/networks", &compute.Network{
+		Name:     "vpc",
+		Peerings: []*compute.NetworkPeering{{Name: "servicenetworking-googleapis-com", Network: "https://www.googleapis.com/compute/v1/projects/tenant/global/networks/servicenetworking"}},
+	})
+	server.Add("global/addresses", &compute.Address{Name: "google-managed-services-vpc", Purpose: "VPC_PEERING", AddressType: "INTERNAL", PrefixLength: 16, Network: network})
+	privateIP := &sqladmin.IpConfiguration{PrivateNetwork: network}
+	server.Add("instances", &sqladmin.DatabaseInstance{
+		Name:         "orders",
+		Region:       testRegion,
+		ReplicaNames: []string{"orders-replica"},
+		Settings:     &sqladmin.Settings{IpConfiguration: privateIP},
+	})
+	server.Add("instances", &sqladmin.DatabaseInstance{
+		Name:               "orders-replica",
+		Region:             testRegion,
+		MasterInstanceName: testProject + ":orders",
+		ReplicaNames:       []string{"orders-replica-cascade"},
+		Settings:           &sqladmin.Settings{IpConfiguration: privateIP},
+	})
+	server.Add("instances", &sqladmin.DatabaseInstance{
+		Name:               "orders-replica-cascade",
+		Region:             testRegion,
+		MasterInstanceName: testProject + ":orders-replica",
+		Settings:           &sqladmin.Settings{IpConfiguration: privateIP},
+	})
+	server.Add("instances", &sqladmin.DatabaseInstance{
+		Name:     "billing",
+		Region:   testRegion,
+		Settings: &sqladmin.Settings{DeletionProtectionEnabled: true},
+	})
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.DisableDeletionProtection = true
+
+	runReport, err := RemoveProjectReport(testConfig, clients)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if remaining := server.Paths(); len(remaining) != 0 {
+		t.Errorf("expected every instance, the peering range and the network to be deleted, remaining: %v", remaining)
+	}
+	order := deleteOrder(server)
+	assertDeletedOnce(t, order)
+	for _, before := range [][]string{
+		{"DELETE instances/orders-replica-cascade", "DELETE instances/orders-replica"},
+		{"DELETE instances/orders-replica", "DELETE instances/orders"},
+		{"DELETE instances/orders", "POST global/networks/vpc/removePeering"},
+		{"PATCH instances/billing", "DELETE instances/billing"},
+	} {
+		assertDeletedBefore(t, order, before[0], before[1])
+	}
+
+	notes := runReport.Resource("SQLInstances").Notes
+	if names := sortedKeys(notes); !reflect.DeepEqual(names, []string{"billing", "orders", "orders-replica", "orders-replica-cascade"}) {
+		t.Errorf("expected the name reuse delay of every deleted instance in the report, got: %v", notes)
+	}
+	if !strings.HasPrefix(notes["orders"], "name can't be reused until ") {
+		t.Errorf("expected the date the name can be reused, got: %v", notes["orders"])
+	}
+}
+
+// TestRemoveProjectSQLInstancesProtected - without --disable-deletion-protection a protected instance is reported and left, and the network after it is still deleted
+func TestRemoveProjectSQLInstancesProtected(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Add("global/networks", &compute.Network{Name: "vpc"})
+	server.Add("instances", &sqladmin.DatabaseInstance{Name: "billing", Region: testRegion, Settings: &sqladmin.Settings{DeletionProtectionEnabled: true}})
+	server.Add("instances", &sqladmin.DatabaseInstance{Name: "scratch", Region: testRegion})
+	testConfig, clients := newTestConfig(t, server)
+
+	runReport, err := RemoveProjectReport(testConfig, clients)
+	if err != nil {
+		t.Fatalf("expected the protected instance to be left without failing the run, got: %v", err)
+	}
+	instanceReport := runReport.Resource("SQLInstances")
+	if !reflect.DeepEqual(instanceReport.Remaining, []string{"billing"}) || instanceReport.Deleted != 1 {
+		t.Errorf("expected scratch deleted and billing remaining, got: %+v", instanceReport)
+	}
+	if note := instanceReport.Notes["billing"]; note != "deletion protection enabled" {
+		t.Errorf("expected billing noted as protected, got: %v", instanceReport.Notes)
+	}
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, []string{"instances/billing"}) {
+		t.Errorf("expected only the protected instance left, got: %v", remaining)
+	}
+	if containsRequest(server, "PATCH", "/instances/billing") {
+		t.Error("expected deletion protection to be left on")
+	}
+}
+
+// TestRemoveProjectSQLInstancesKeptReplica - a primary is kept along with any replica the filters keep, rather than failing to delete it
+func TestRemoveProjectSQLInstancesKeptReplica(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Add("instances", &sqladmin.DatabaseInstance{Name: "orders", Region: testRegion, ReplicaNames: []string{"orders-replica"}})
+	server.Add("instances", &sqladmin.DatabaseInstance{Name: "orders-replica", Region: testRegion, MasterInstanceName: testProject + ":orders", ReplicaNames: []string{"reporting"}})
+	server.Add("instances", &sqladmin.DatabaseInstance{Name: "reporting", Region: testRegion, MasterInstanceName: testProject + ":orders-replica"})
+	server.Add("instances", &sqladmin.DatabaseInstance{Name: "scratch", Region: testRegion})
+	testConfig, clients := newTestConfig(t, server)
+	testConfig.NamePatterns = []*regexp.Regexp{regexp.MustCompile(`^(orders.*|scratch)$`)}
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"instances/orders", "instances/orders-replica", "instances/reporting"}
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected the chain above the kept replica to be kept, got: %v", remaining)
+	}
+}
+
+// TestRemoveProjectSQLAdminDisabled - a project without the sql admin api enabled has no instances, and the rest of it is still deleted
+func TestRemoveProjectSQLAdminDisabled(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Add("zones/"+testZone+"/disks", &compute.Disk{Name: "scratch"})
+	testConfig, clients := newTestConfig(t, server)
+	server.Fail("/sql/v1beta4/projects/", http.StatusForbidden, "accessNotConfigured", "Cloud SQL Admin API has not been used in project "+testProject+" before or it is disabled.")
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+	if remaining := server.Paths(); len(remaining) != 0 {
+		t.Errorf("expected the disk to be deleted, remaining: %v", remaining)
+	}
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
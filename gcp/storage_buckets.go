@@ -0,0 +1,309 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"github.com/arehmandev/gcp-nuke/report"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/storage/v1"
+)
+
+// objectDeletions - parallel object deletions per bucket, on top of the --max-concurrency slot the bucket holds
+const objectDeletions = 32
+
+// StorageBuckets - buckets are emptied of every object version before they are deleted
+type StorageBuckets struct {
+	serviceClient *storage.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+// storageBucketProperties -
+type storageBucketProperties struct {
+	location string
+	// userProject - billed for requests to requester pays buckets, empty otherwise
+	userProject string
+}
+
+func init() {
+	register(func() Resource {
+		return &StorageBuckets{}
+	})
+}
+
+// Name - Name of the resourceLister for StorageBuckets
+func (c *StorageBuckets) Name() string {
+	return "StorageBuckets"
+}
+
+// ToSlice - Name of the resourceLister for StorageBuckets
+func (c *StorageBuckets) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *StorageBuckets) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.Storage()
+	return err
+}
+
+// List - Returns a list of all StorageBuckets
+func (c *StorageBuckets) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	bucketListCall := c.serviceClient.Buckets.List(c.base.config.Project)
+	err := bucketListCall.Pages(ctx, func(bucketList *storage.Buckets) error {
+		for _, bucket := range bucketList.Items {
+			if c.base.skip(c.Name(), listedItem{name: bucket.Name, labels: bucket.Labels, created: bucket.TimeCreated}) {
+				continue
+			}
+			if bucket.RetentionPolicy != nil && bucket.RetentionPolicy.IsLocked {
+				log.Printf("[Warning] Bucket %v has a locked retention policy, objects younger than %v can't be deleted until they expire", bucket.Name, time.Duration(bucket.RetentionPolicy.RetentionPeriod)*time.Second)
+			}
+			bucketResource := storageBucketProperties{
+				location: strings.ToLower(bucket.Location),
+			}
+			if bucket.Billing != nil && bucket.Billing.RequesterPays {
+				bucketResource.userProject = c.base.config.Project
+			}
+			c.resourceMap.Store(bucket.Name, bucketResource)
+		}
+		return nil
+	})
+	if err != nil {
+		if c.base.apiDisabled(err) {
+			return c.ToSlice(), nil
+		}
+		return nil, err
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *StorageBuckets) Dependencies() []string {
+	return []string{}
+}
+
+// Remove - removes the lifecycle rules, notifications and an unlocked retention policy, deletes every object version, then the bucket
+// Objects under a hold or the locked retention policy are left, and the bucket is left in place with a note of what's blocking it
+func (c *StorageBuckets) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		bucketName := key.(string)
+		bucketResource := value.(storageBucketProperties)
+
+		// Parallel bucket deletion
+		errs.Go(bucketName, bucketResource.location, func(ctx context.Context) error {
+			bucketGetCall := c.serviceClient.Buckets.Get(bucketName)
+			if bucketResource.userProject != "" {
+				bucketGetCall.UserProject(bucketResource.userProject)
+			}
+			bucket, err := bucketGetCall.Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			if err := c.removeConfig(ctx, bucket, bucketResource.userProject); err != nil {
+				return err
+			}
+			blocked, err := c.removeObjects(ctx, bucket, bucketResource.userProject)
+			if err != nil {
+				return err
+			}
+			if blocked.total() > 0 {
+				// Nothing gcp-nuke can do will delete the bucket until the holds are released or the retention expires, so it isn't an error
+				log.Printf("[Warning] Bucket %v is blocked, %v", bucketName, blocked)
+				if runReport := report.FromContext(ctx); runReport != nil {
+					runReport.AddNote(c.Name(), bucketName, "blocked, "+blocked.String())
+				}
+				return errLeftInPlace
+			}
+
+			deleteCall := c.serviceClient.Buckets.Delete(bucketName)
+			if bucketResource.userProject != "" {
+				deleteCall.UserProject(bucketResource.userProject)
+			}
+			if err := deleteCall.Context(ctx).Do(); err != nil {
+				return err
+			}
+			log.Printf("[Info] Resource deleted %v [type: %v project: %v location: %v]", bucketName, c.Name(), c.base.config.Project, bucketResource.location)
+			c.resourceMap.Delete(bucketName)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
+
+// removeConfig - stops the lifecycle rules and notifications firing whilst the bucket is emptied
+// An unlocked retention policy is removed too, a locked one can't be
+func (c *StorageBuckets) removeConfig(ctx context.Context, bucket *storage.Bucket, userProject string) error {
+	patch := &storage.Bucket{}
+	if bucket.Lifecycle != nil && len(bucket.Lifecycle.Rule) > 0 {
+		patch.NullFields = append(patch.NullFields, "Lifecycle")
+	}
+	if bucket.RetentionPolicy != nil && !bucket.RetentionPolicy.IsLocked {
+		patch.NullFields = append(patch.NullFields, "RetentionPolicy")
+	}
+	if len(patch.NullFields) > 0 {
+		patchCall := c.serviceClient.Buckets.Patch(bucket.Name, patch)
+		if userProject != "" {
+			patchCall.UserProject(userProject)
+		}
+		if _, err := patchCall.Context(ctx).Do(); err != nil {
+			return fmt.Errorf("removing the lifecycle rules and retention policy of bucket %v: %v", bucket.Name, err)
+		}
+	}
+
+	notificationListCall := c.serviceClient.Notifications.List(bucket.Name)
+	if userProject != "" {
+		notificationListCall.UserProject(userProject)
+	}
+	notifications, err := notificationListCall.Context(ctx).Do()
+	if err != nil {
+		return err
+	}
+	for _, notification := range notifications.Items {
+		deleteCall := c.serviceClient.Notifications.Delete(bucket.Name, notification.Id)
+		if userProject != "" {
+			deleteCall.UserProject(userProject)
+		}
+		if err := deleteCall.Context(ctx).Do(); err != nil && !isNotFound(err) {
+			return fmt.Errorf("removing notification %v of bucket %v: %v", notification.Id, bucket.Name, err)
+		}
+	}
+	return nil
+}
+
+// removeObjects - deletes every version of every object, a page at a time with up to objectDeletions in parallel
+// Returns the objects which are protected, and were left
+func (c *StorageBuckets) removeObjects(ctx context.Context, bucket *storage.Bucket, userProject string) (bucketBlockers, error) {
+	lockedRetention := bucket.RetentionPolicy != nil && bucket.RetentionPolicy.IsLocked
+	var blocked bucketBlockers
+	deleted := 0
+
+	objectListCall := c.serviceClient.Objects.List(bucket.Name).Versions(true)
+	if userProject != "" {
+		objectListCall.UserProject(userProject)
+	}
+	err := objectListCall.Pages(ctx, func(objectList *storage.Objects) error {
+		deletions := errgroup.Group{}
+		slots := semaphore.NewWeighted(objectDeletions)
+		for _, object := range objectList.Items {
+			if blocked.add(object, lockedRetention) {
+				continue
+			}
+			if err := slots.Acquire(ctx, 1); err != nil {
+				break
+			}
+			object := object
+			deletions.Go(func() error {
+				defer slots.Release(1)
+				deleteCall := c.serviceClient.Objects.Delete(bucket.Name, object.Name).Generation(object.Generation)
+				if userProject != "" {
+					deleteCall.UserProject(userProject)
+				}
+				if err := deleteCall.Context(ctx).Do(); err != nil && !isNotFound(err) {
+					return fmt.Errorf("deleting object %v#%v of bucket %v: %v", object.Name, object.Generation, bucket.Name, err)
+				}
+				return nil
+			})
+		}
+		if err := deletions.Wait(); err != nil {
+			return err
+		}
+		deleted += len(objectList.Items)
+		log.Printf("[Info] Resource currently being emptied %v [type: %v project: %v] (%v object versions deleted)", bucket.Name, c.Name(), c.base.config.Project, deleted-blocked.total())
+		return ctx.Err()
+	})
+	return blocked, err
+}
+
+// bucketBlockers - counts of the objects which can't be deleted yet, by what protects them
+type bucketBlockers struct {
+	eventBasedHold  int
+	temporaryHold   int
+	lockedRetention int
+	// retainedUntil - the latest retention expiry of an object under the locked retention policy
+	retainedUntil string
+}
+
+// add - whether the object is protected, counting it if so
+func (b *bucketBlockers) add(object *storage.Object, lockedRetention bool) bool {
+	switch {
+	case object.EventBasedHold:
+		b.eventBasedHold++
+	case object.TemporaryHold:
+		b.temporaryHold++
+	case lockedRetention && retainedAfter(object.RetentionExpirationTime, now()):
+		b.lockedRetention++
+		if object.RetentionExpirationTime > b.retainedUntil {
+			b.retainedUntil = object.RetentionExpirationTime
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func (b *bucketBlockers) total() int {
+	return b.eventBasedHold + b.temporaryHold + b.lockedRetention
+}
+
+// String - e.g. 2 objects under an event-based hold, 1 object under the locked retention policy until 2027-01-01T00:00:00Z
+func (b bucketBlockers) String() string {
+	reasons := []string{}
+	if b.eventBasedHold > 0 {
+		reasons = append(reasons, fmt.Sprintf("%v under an event-based hold", objectCount(b.eventBasedHold)))
+	}
+	if b.temporaryHold > 0 {
+		reasons = append(reasons, fmt.Sprintf("%v under a temporary hold", objectCount(b.temporaryHold)))
+	}
+	if b.lockedRetention > 0 {
+		reasons = append(reasons, fmt.Sprintf("%v under the locked retention policy until %v", objectCount(b.lockedRetention), b.retainedUntil))
+	}
+	return strings.Join(reasons, ", ")
+}
+
+func objectCount(count int) string {
+	if count == 1 {
+		return "1 object"
+	}
+	return fmt.Sprintf("%v objects", count)
+}
+
+// retainedAfter - whether the retention expiry is after the time, objects without a valid expiry are treated as retained
+func retainedAfter(retentionExpiration string, after time.Time) bool {
+	expiry, err := time.Parse(time.RFC3339, retentionExpiration)
+	if err != nil {
+		return true
+	}
+	return expiry.After(after)
+}
+
+// isNotFound - whether the api error is a 404, e.g. an object version already removed by a lifecycle rule
+func isNotFound(err error) bool {
+	apiErr, ok := err.(*googleapi.Error)
+	return ok && apiErr.Code == http.StatusNotFound
+}
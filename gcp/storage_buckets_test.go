@@ -0,0 +1,84 @@
+package gcp
+
+import (
+	"reflect"
+	"testing"
+
+	"google.golang.org/api/storage/v1"
+)
+
+func TestRemoveProjectStorageBuckets(t *testing.T) {
+	server := newTestServer()
+	defer server.Close()
+	server.Add("b", &storage.Bucket{
+		Name:            "logs",
+		Lifecycle:       &storage.BucketLifecycle{Rule: []*storage.BucketLifecycleRule{{Action: &storage.BucketLifecycleRuleAction{Type: "Delete"}}}},
+		RetentionPolicy: &storage.BucketRetentionPolicy{RetentionPeriod: 3600},
+	})
+	server.Add("b/logs/notificationConfigs", &storage.Notification{Id: "1", Topic: "//pubsub.googleapis.com/projects/test-nuke-123456/topics/logs"})
+	server.AddObject("logs", &storage.Object{Name: "2026/10/19/app.log", Generation: 2})
+	server.AddObject("logs", &storage.Object{Name: "2026/10/19/app.log", Generation: 1, TimeDeleted: "2026-10-19T10:00:00Z"})
+	server.AddObject("logs", &storage.Object{Name: "index.html", Generation: 3})
+	server.Add("b", &storage.Bucket{Name: "legal"})
+	server.AddObject("legal", &storage.Object{Name: "contract.pdf", Generation: 1, EventBasedHold: true})
+	server.AddObject("legal", &storage.Object{Name: "draft.pdf", Generation: 1})
+	server.Add("b", &storage.Bucket{Name: "archive", RetentionPolicy: &storage.BucketRetentionPolicy{IsLocked: true, RetentionPeriod: 86400 * 365 * 100}})
+	server.AddObject("archive", &storage.Object{Name: "old.tar", Generation: 1, RetentionExpirationTime: "2020-01-01T00:00:00Z"})
+	server.AddObject("archive", &storage.Object{Name: "new.tar", Generation: 1, RetentionExpirationTime: "2120-01-01T00:00:00Z"})
+	testConfig, clients := newTestConfig(t, server)
+
+	runReport, err := RemoveProjectReport(testConfig, clients)
+	if err != nil {
+		t.Fatalf("expected the protected buckets to be left without failing the run, got: %v", err)
+	}
+	bucketReport := runReport.Resource("StorageBuckets")
+	if !reflect.DeepEqual(bucketReport.Remaining, []string{"archive", "legal"}) || bucketReport.Deleted != 1 {
+		t.Errorf("expected logs deleted, and archive and legal remaining, got: %+v", bucketReport)
+	}
+	notes := map[string]string{
+		"archive": "blocked, 1 object under the locked retention policy until 2120-01-01T00:00:00Z",
+		"legal":   "blocked, 1 object under an event-based hold",
+	}
+	if !reflect.DeepEqual(bucketReport.Notes, notes) {
+		t.Errorf("expected archive and legal noted as blocked, got: %v", bucketReport.Notes)
+	}
+
+	expected := []string{
+		"b/archive",
+		"b/archive/o/new.tar#1",
+		"b/legal",
+		"b/legal/o/contract.pdf#1",
+	}
+	if remaining := server.Paths(); !reflect.DeepEqual(remaining, expected) {
+		t.Errorf("expected only the protected objects and their buckets left, got: %v", remaining)
+	}
+	if !containsRequest(server, "PATCH", "/b/logs") {
+		t.Error("expected the lifecycle rules and retention policy of logs to be removed")
+	}
+}
+
+func TestBucketBlockers(t *testing.T) {
+	blocked := bucketBlockers{}
+	objects := []*storage.Object{
+		{Name: "held", EventBasedHold: true},
+		{Name: "temporary", TemporaryHold: true},
+		{Name: "also-temporary", TemporaryHold: true},
+		{Name: "retained", RetentionExpirationTime: "2120-01-01T00:00:00Z"},
+		{Name: "retained-longer", RetentionExpirationTime: "2121-01-01T00:00:00Z"},
+		{Name: "expired", RetentionExpirationTime: "2020-01-01T00:00:00Z"},
+	}
+	deletable := []string{}
+	for _, object := range objects {
+		if !blocked.add(object, true) {
+			deletable = append(deletable, object.Name)
+		}
+	}
+
+	if !reflect.DeepEqual(deletable, []string{"expired"}) {
+		t.Errorf("expected only the expired object to be deletable, got: %v", deletable)
+	}
+	expected := "1 object under an event-based hold, 2 objects under a temporary hold, 2 objects under the locked retention policy until 2121-01-01T00:00:00Z"
+	if blocked.String() != expected {
+		t.Errorf("expected %q, got: %q", expected, blocked.String())
+	}
+}
@@ -0,0 +1,150 @@
+package gcp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/report"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+)
+
+// throttle - the throttling of one run, shared by every resource type - one token bucket per API (compute, container...) and one pool of deletion slots
+// Each run builds its own from its config, so concurrent runs of gcp-nuke serve don't share or replace each other's limits
+type throttle struct {
+	mutex     sync.Mutex
+	rateLimit rate.Limit
+	burst     int
+	limiters  map[string]*rate.Limiter
+	slots     *semaphore.Weighted
+}
+
+// newThrottle - applies the rate limit and max concurrency from the config to all APIs
+func newThrottle(config config.Config) *throttle {
+	t := &throttle{
+		rateLimit: rate.Inf,
+		burst:     1,
+		limiters:  make(map[string]*rate.Limiter),
+	}
+	if config.RateLimit > 0 {
+		t.rateLimit = rate.Limit(config.RateLimit)
+		t.burst = int(math.Ceil(config.RateLimit))
+	}
+
+	maxConcurrency := int64(math.MaxInt64)
+	if config.MaxConcurrency > 0 {
+		maxConcurrency = int64(config.MaxConcurrency)
+	}
+	t.slots = semaphore.NewWeighted(maxConcurrency)
+	return t
+}
+
+// apiLimiter - returns the token bucket shared by every caller of the api, creating it on first use
+func (t *throttle) apiLimiter(api string) *rate.Limiter {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	limiter, exists := t.limiters[api]
+	if !exists {
+		limiter = rate.NewLimiter(t.rateLimit, t.burst)
+		t.limiters[api] = limiter
+	}
+	return limiter
+}
+
+type throttleKey struct{}
+
+// withThrottle - the run context, carrying the deletion slots of the run to every deletion group
+func withThrottle(ctx context.Context, t *throttle) context.Context {
+	return context.WithValue(ctx, throttleKey{}, t)
+}
+
+// deletionSlots - the deletion slots of the run, unlimited outside of a run
+func deletionSlots(ctx context.Context) *semaphore.Weighted {
+	if t, ok := ctx.Value(throttleKey{}).(*throttle); ok {
+		return t.slots
+	}
+	return semaphore.NewWeighted(math.MaxInt64)
+}
+
+// rateLimitedTransport - waits for a token from the api limiter before every request
+type rateLimitedTransport struct {
+	limiter *rate.Limiter
+	base    http.RoundTripper
+}
+
+// RoundTrip -
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.limiter.Wait(req.Context()); err != nil {
+		return nil, err
+	}
+	return t.base.RoundTrip(req)
+}
+
+// errLeftInPlace - returned by a deletion which leaves the item in place on purpose, e.g. a bucket blocked by a locked retention policy
+// The item is neither counted as deleted nor failed, and is reported as remaining
+var errLeftInPlace = errors.New("left in place")
+
+// deletionGroup - errgroup for parallel item deletion, where every item waits for one of the --max-concurrency slots
+type deletionGroup struct {
+	errs         errgroup.Group
+	ctx          context.Context
+	resourceType string
+	timeout      time.Duration
+	slots        *semaphore.Weighted
+}
+
+func newDeletionGroup(ctx context.Context, resourceType string, timeout time.Duration) *deletionGroup {
+	return &deletionGroup{
+		ctx:          ctx,
+		resourceType: resourceType,
+		timeout:      timeout,
+		slots:        deletionSlots(ctx),
+	}
+}
+
+// Go - runs the deletion of the named item once a slot is free, the deletion context is cancelled after the item timeout
+// Deletions are counted as deleted or failed in the metrics and the run report, and traced with a span per item
+func (g *deletionGroup) Go(name, location string, deletion func(ctx context.Context) error) {
+	g.errs.Go(func() (err error) {
+		ctx, span := tracer().Start(g.ctx, "Delete "+g.resourceType, trace.WithAttributes(
+			typeKey.String(g.resourceType),
+			nameKey.String(name),
+			locationKey.String(location),
+		))
+		defer func() { endSpan(span, err) }()
+
+		if err := g.slots.Acquire(ctx, 1); err != nil {
+			return err
+		}
+		defer g.slots.Release(1)
+
+		ctx, cancel := context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+		err = deletion(ctx)
+		if err == errLeftInPlace {
+			return nil
+		}
+		if err != nil {
+			resourcesFailed.WithLabelValues(g.resourceType).Inc()
+			return err
+		}
+		resourcesDeleted.WithLabelValues(g.resourceType).Inc()
+		if runReport := report.FromContext(ctx); runReport != nil {
+			runReport.AddDeleted(g.resourceType)
+		}
+		return nil
+	})
+}
+
+// Wait - waits for all deletions to complete, and returns the first non nil error
+func (g *deletionGroup) Wait() error {
+	return g.errs.Wait()
+}
@@ -0,0 +1,66 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer - spans go to the current global tracer provider, which drops them unless SetupTracing has been called
+func tracer() trace.Tracer {
+	return otel.Tracer("github.com/arehmandev/gcp-nuke/gcp")
+}
+
+// SetupTracing - exports the spans of every run with the exporter, either otlp or stdout
+// The otlp exporter is configured with the standard OTEL_EXPORTER_OTLP_* environment variables, and sends to
+// localhost:4318 by default. The returned shutdown flushes any buffered spans, so call it before exiting.
+func SetupTracing(ctx context.Context, exporter string, stdout io.Writer) (shutdown func(context.Context) error, err error) {
+	var spanExporter sdktrace.SpanExporter
+	switch exporter {
+	case "otlp":
+		spanExporter, err = otlptracehttp.New(ctx)
+	case "stdout":
+		spanExporter, err = stdouttrace.New(stdouttrace.WithWriter(stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %v, expected otlp or stdout", exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(spanExporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceNameKey.String("gcp-nuke"))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// endSpan - records the error, if any, and ends the span
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Span attributes
+var (
+	projectKey    = attribute.Key("gcp.project")
+	typeKey       = attribute.Key("gcp_nuke.resource.type")
+	nameKey       = attribute.Key("gcp_nuke.resource.name")
+	locationKey   = attribute.Key("gcp_nuke.resource.location")
+	dependencyKey = attribute.Key("gcp_nuke.dependency")
+	statusKey     = attribute.Key("gcp.operation.status")
+)
@@ -0,0 +1,93 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRemoveProjectTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	defer otel.SetTracerProvider(previous)
+
+	server := newTestServer()
+	defer server.Close()
+	seedProject(server)
+	testConfig, clients := newTestConfig(t, server)
+
+	if err := RemoveProject(testConfig, clients); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		key := span.Name()
+		for _, attr := range span.Attributes() {
+			if attr.Key == nameKey {
+				key += " " + attr.Value.AsString()
+			}
+		}
+		spans[key] = span
+	}
+
+	root, exists := spans["RemoveProject"]
+	if !exists {
+		t.Fatal("expected a root span for the project")
+	}
+	// Every span belongs to the trace of the project, with the item span under the resource type span
+	for key, span := range spans {
+		if span.SpanContext().TraceID() != root.SpanContext().TraceID() {
+			t.Errorf("expected %v to be part of the project trace", key)
+		}
+	}
+	disk, exists := spans["Delete ComputeDisks scratch"]
+	if !exists {
+		t.Fatalf("expected a span for the disk deletion, got: %v", spanNames(spans))
+	}
+	if disk.Parent().SpanID() != spans["ComputeDisks"].SpanContext().SpanID() {
+		t.Error("expected the disk deletion span to be a child of the ComputeDisks span")
+	}
+	for _, expected := range []string{"ComputeNetworks", "Wait for ComputeSubnetworks", "Poll operation"} {
+		if _, exists := spans[expected]; !exists {
+			t.Errorf("expected a %v span, got: %v", expected, spanNames(spans))
+		}
+	}
+}
+
+func TestSetupTracingStdout(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	output := &bytes.Buffer{}
+	shutdown, err := SetupTracing(context.Background(), "stdout", output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, span := tracer().Start(context.Background(), "RemoveProject")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(output.String(), `"Name": "RemoveProject"`) {
+		t.Errorf("expected the span to be exported to stdout, got: %v", output.String())
+	}
+	if _, err := SetupTracing(context.Background(), "zipkin", output); err == nil {
+		t.Error("expected an error for an unknown exporter")
+	}
+}
+
+func spanNames(spans map[string]sdktrace.ReadOnlySpan) []string {
+	names := []string{}
+	for name := range spans {
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,123 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"golang.org/x/sync/syncmap"
+	"google.golang.org/api/vpcaccess/v1"
+)
+
+// VPCAccessConnectors - Serverless VPC Access connectors of every region, keyed by region/name
+// A connector holds a /28 of its network, or its own subnetwork, until it is deleted. Connectors have neither labels nor a creation time
+type VPCAccessConnectors struct {
+	serviceClient *vpcaccess.Service
+	base          ResourceBase
+	resourceMap   syncmap.Map
+}
+
+func init() {
+	register(func() Resource {
+		return &VPCAccessConnectors{}
+	})
+}
+
+// Name - Name of the resourceLister for VPCAccessConnectors
+func (c *VPCAccessConnectors) Name() string {
+	return "VPCAccessConnectors"
+}
+
+// ToSlice - Name of the resourceLister for VPCAccessConnectors
+func (c *VPCAccessConnectors) ToSlice() (slice []string) {
+	return helpers.SortedSyncMapKeys(&c.resourceMap)
+
+}
+
+// Setup - populates the struct
+func (c *VPCAccessConnectors) Setup(config config.Config, clients *Clients) (err error) {
+	c.base.config = config
+	c.serviceClient, err = clients.VPCAccess()
+	return err
+}
+
+// List - Returns a list of all VPCAccessConnectors
+func (c *VPCAccessConnectors) List(ctx context.Context, refreshCache bool) ([]string, error) {
+	if !refreshCache {
+		return c.ToSlice(), nil
+	}
+	// Refresh resource map
+	c.resourceMap = sync.Map{}
+
+	for _, region := range c.base.config.Regions {
+		connectorListCall := c.serviceClient.Projects.Locations.Connectors.List(fmt.Sprintf("projects/%v/locations/%v", c.base.config.Project, region))
+		err := connectorListCall.Pages(ctx, func(connectorList *vpcaccess.ListConnectorsResponse) error {
+			for _, connector := range connectorList.Connectors {
+				connectorKey := regionalKey(region, keyName(connector.Name))
+				// Connectors have neither labels nor a creation time, so there's no telling whether they expired
+				if c.base.needsCreationTime() {
+					log.Printf("[Skipping] VPC access connector %v, connectors have no labels or creation time for --ttl-label", connectorKey)
+					continue
+				}
+				if c.base.skip(c.Name(), listedItem{name: connectorKey, monthlyCost: connectorCost(c.base.prices(), connector, region)}) {
+					continue
+				}
+				c.resourceMap.Store(connectorKey, connector.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			if c.base.apiDisabled(err) {
+				return c.ToSlice(), nil
+			}
+			return nil, err
+		}
+	}
+	return c.ToSlice(), nil
+}
+
+// Dependencies - Returns a List of resource names to check for
+func (c *VPCAccessConnectors) Dependencies() []string {
+	a := CloudRunServices{}
+	b := CloudRunJobs{}
+	d := CloudFunctions{}
+	return []string{a.Name(), b.Name(), d.Name()}
+}
+
+// Remove -
+func (c *VPCAccessConnectors) Remove(ctx context.Context) error {
+
+	// Removal logic
+	errs := newDeletionGroup(ctx, c.Name(), c.base.config.TimeoutFor(c.Name()))
+
+	c.resourceMap.Range(func(key, value interface{}) bool {
+		connectorKey := key.(string)
+		connectorName := value.(string)
+		region := strings.Split(connectorName, "/")[3]
+
+		// Parallel connector deletion
+		errs.Go(connectorKey, region, func(ctx context.Context) error {
+			operation, err := c.serviceClient.Projects.Locations.Connectors.Delete(connectorName).Context(ctx).Do()
+			if err != nil {
+				return err
+			}
+			description := fmt.Sprintf("%v [type: %v project: %v region: %v]", keyName(connectorKey), c.Name(), c.base.config.Project, region)
+			err = c.base.waitForOperation(ctx, c.Name(), description, longRunningOperationStatus(operation.Name, func() (interface{}, error) {
+				return c.serviceClient.Projects.Locations.Operations.Get(operation.Name).Context(ctx).Do()
+			}))
+			if err != nil {
+				return err
+			}
+			c.resourceMap.Delete(connectorKey)
+			return nil
+		})
+		return true
+	})
+	// Wait for all deletions to complete, and return the first non nil error
+	err := errs.Wait()
+	return err
+}
@@ -1,11 +1,13 @@
 package helpers
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
 	"sort"
 	"syscall"
+	"time"
 
 	"golang.org/x/sync/syncmap"
 )
@@ -42,13 +44,28 @@ func SortedSyncMapKeys(parMap *syncmap.Map) (output []string) {
 	return output
 }
 
-// SetupCloseHandler - allows manual termination
-func SetupCloseHandler() {
+// SetupCloseHandler - allows manual termination, the first Ctrl+C cancels the run so it can still report, the second exits
+func SetupCloseHandler(cancel func()) {
 	c := make(chan os.Signal, 2)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
+		<-c
+		fmt.Println("\r- Ctrl+C pressed in Terminal - cancelling, press again to exit immediately")
+		cancel()
 		<-c
 		fmt.Println("\r- Ctrl+C pressed in Terminal - premature termination")
 		os.Exit(1)
 	}()
 }
+
+// SleepContext - sleeps for the duration, returning the context error early if it's cancelled or its deadline passes
+func SleepContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
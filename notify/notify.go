@@ -0,0 +1,190 @@
+// Package notify - sends the plan and the result of a run to webhooks, either as json or as slack messages
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/helpers"
+	"github.com/arehmandev/gcp-nuke/report"
+)
+
+// Events a notification can subscribe to
+const (
+	// EventPlan - the plan, sent before anything is deleted by a destructive run
+	EventPlan = "plan"
+	// EventCompleted - the run finished without errors
+	EventCompleted = "completed"
+	// EventFailed - the run finished with an error, timed out or was interrupted
+	EventFailed = "failed"
+)
+
+// Formats of the webhook payload
+const (
+	// FormatWebhook - json with the event, the message and the full report
+	FormatWebhook = "webhook"
+	// FormatSlack - slack compatible json with the message as text
+	FormatSlack = "slack"
+)
+
+// sendTimeout - how long to wait on a webhook, a slow webhook shouldn't hold up a run
+const sendTimeout = 30 * time.Second
+
+// defaultTemplate - summary of the run, or of the plan, used when a notification has no template
+const defaultTemplate = `{{if eq .Event "plan" -}}
+gcp-nuke is about to delete from project {{.Project}}{{with .MonthlyCost}}, saving an estimated {{money .}}/month{{end}}:
+{{range .ByOwner}}{{.Owner}}{{with .MonthlyCost}} ({{money .}}/month){{end}}:
+{{range .Resources}}- {{.Type}}: {{join .Items ", "}}{{with .MonthlyCost}} ({{money .}}/month){{end}}
+{{end}}{{end}}
+{{- else -}}
+gcp-nuke {{if .DryRun}}dry run {{end}}{{.Event}} for project {{.Project}} in {{round .Duration}}{{with .Error}}: {{.}}{{end}}
+{{range .Planned}}- {{.Type}}: {{if $.DryRun}}{{len .Items}} would be deleted{{else}}{{.Deleted}} of {{len .Items}} deleted{{end}}
+{{- with .Snapshots}}, {{len .}} snapshotted{{end}}{{range $item, $note := .Notes}}, {{$item}}: {{$note}}{{end}}{{with .Remaining}}, remaining: {{join . ", "}}{{end}}{{with .Error}}, error: {{.}}{{end}}
+{{end}}
+{{- end}}`
+
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"round": func(d time.Duration) time.Duration {
+		return d.Round(time.Second)
+	},
+	"money": func(usd float64) string {
+		return fmt.Sprintf("$%.2f", usd)
+	},
+}
+
+// Message - data available to templates, e.g. {{.Event}} {{.Project}} {{range .Failures}}{{.Type}}{{end}}
+type Message struct {
+	Event string
+	*report.Report
+}
+
+// Notifier - a single webhook
+type Notifier struct {
+	url      string
+	format   string
+	events   []string
+	template *template.Template
+	client   *http.Client
+}
+
+// New - validates the notifications and parses their templates
+func New(notifications []config.Notification) ([]*Notifier, error) {
+	notifiers := []*Notifier{}
+	for _, notification := range notifications {
+		if notification.URL == "" {
+			return nil, fmt.Errorf("notification without a url")
+		}
+
+		format := notification.Format
+		if format == "" {
+			format = FormatWebhook
+		}
+		if format != FormatWebhook && format != FormatSlack {
+			return nil, fmt.Errorf("unknown notification format %q, expected %v or %v", format, FormatWebhook, FormatSlack)
+		}
+
+		events := notification.Events
+		if len(events) == 0 {
+			events = []string{EventCompleted, EventFailed}
+		}
+		for _, event := range events {
+			if !helpers.SliceContains([]string{EventPlan, EventCompleted, EventFailed}, event) {
+				return nil, fmt.Errorf("unknown notification event %q, expected %v, %v or %v", event, EventPlan, EventCompleted, EventFailed)
+			}
+		}
+
+		text := notification.Template
+		if text == "" {
+			text = defaultTemplate
+		}
+		parsed, err := template.New("notification").Funcs(templateFuncs).Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notification template: %v", err)
+		}
+
+		notifiers = append(notifiers, &Notifier{
+			url:      notification.URL,
+			format:   format,
+			events:   events,
+			template: parsed,
+			client:   &http.Client{Timeout: sendTimeout},
+		})
+	}
+	return notifiers, nil
+}
+
+// Wants - whether the notifier subscribed to the event
+func (n *Notifier) Wants(event string) bool {
+	return helpers.SliceContains(n.events, event)
+}
+
+// Send - posts the event to the webhook
+func (n *Notifier) Send(ctx context.Context, event string, runReport *report.Report) error {
+	text := &bytes.Buffer{}
+	if err := n.template.Execute(text, Message{Event: event, Report: runReport}); err != nil {
+		return err
+	}
+
+	var payload interface{}
+	switch n.format {
+	case FormatSlack:
+		payload = map[string]string{"text": text.String()}
+	default:
+		payload = map[string]interface{}{
+			"event":  event,
+			"text":   text.String(),
+			"report": runReport,
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req.WithContext(ctx))
+	if err != nil {
+		// Webhook urls usually carry a secret, so only the host is logged
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("posting to %v: %v", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		response, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("webhook returned %v: %s", resp.Status, response)
+	}
+	return nil
+}
+
+// Notify - sends the event to every notifier subscribed to it
+// Failures are only logged, as a run shouldn't fail because a webhook is down
+func Notify(notifiers []*Notifier, event string, runReport *report.Report) {
+	for _, notifier := range notifiers {
+		if !notifier.Wants(event) {
+			continue
+		}
+		// The run context may already be cancelled, e.g. when notifying of an interrupted run
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		if err := notifier.Send(ctx, event, runReport); err != nil {
+			log.Printf("[Error] Sending %v notification: %v", event, err)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,131 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/arehmandev/gcp-nuke/config"
+	"github.com/arehmandev/gcp-nuke/report"
+)
+
+// receiver - local webhook collecting the json payloads posted to it
+type receiver struct {
+	*httptest.Server
+	payloads []map[string]interface{}
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := ioutil.ReadAll(req.Body)
+		payload := map[string]interface{}{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("invalid payload %s: %v", body, err)
+		}
+		r.payloads = append(r.payloads, payload)
+	}))
+	return r
+}
+
+func testReport() *report.Report {
+	runReport := report.New("test-nuke-123456", false)
+	runReport.SetItems("ComputeDisks", []string{"boot", "scratch"})
+	runReport.SetItems("ComputeFirewalls", []string{})
+	runReport.AddDeleted("ComputeDisks")
+	runReport.SetResult("ComputeDisks", []string{"boot"}, errors.New("resourceInUseByAnotherResource"))
+	runReport.Finish(errors.New("run failed"))
+	return runReport
+}
+
+func TestSendWebhook(t *testing.T) {
+	webhook := newReceiver(t)
+	defer webhook.Close()
+	notifiers, err := New([]config.Notification{{URL: webhook.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := notifiers[0].Send(context.Background(), EventFailed, testReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(webhook.payloads) != 1 {
+		t.Fatalf("expected a single payload, got: %v", webhook.payloads)
+	}
+	payload := webhook.payloads[0]
+	if payload["event"] != EventFailed {
+		t.Errorf("expected the failed event, got: %v", payload["event"])
+	}
+	text := payload["text"].(string)
+	for _, expected := range []string{"failed for project test-nuke-123456", "ComputeDisks: 1 of 2 deleted, remaining: boot, error: resourceInUseByAnotherResource"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("expected %q in the message:\n%v", expected, text)
+		}
+	}
+	if strings.Contains(text, "ComputeFirewalls") {
+		t.Errorf("expected resource types with nothing to delete to be left out:\n%v", text)
+	}
+	if payload["report"].(map[string]interface{})["project"] != "test-nuke-123456" {
+		t.Errorf("expected the full report in the payload, got: %v", payload["report"])
+	}
+}
+
+func TestSendSlackTemplate(t *testing.T) {
+	slack := newReceiver(t)
+	defer slack.Close()
+	notifiers, err := New([]config.Notification{{
+		URL:      slack.URL,
+		Format:   FormatSlack,
+		Template: `{{.Event}} {{.Project}}{{range .Failures}} {{.Type}}{{end}}`,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := notifiers[0].Send(context.Background(), EventFailed, testReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{"text": "failed test-nuke-123456 ComputeDisks"}
+	if len(slack.payloads) != 1 || len(slack.payloads[0]) != 1 || slack.payloads[0]["text"] != expected["text"] {
+		t.Errorf("expected %v, got: %v", expected, slack.payloads)
+	}
+}
+
+func TestNotifySubscribedEvents(t *testing.T) {
+	webhook := newReceiver(t)
+	defer webhook.Close()
+	notifiers, err := New([]config.Notification{{URL: webhook.URL}, {URL: webhook.URL, Events: []string{EventPlan}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Notify(notifiers, EventPlan, testReport())
+	Notify(notifiers, EventCompleted, testReport())
+
+	if len(webhook.payloads) != 2 || webhook.payloads[0]["event"] != EventPlan || webhook.payloads[1]["event"] != EventCompleted {
+		t.Errorf("expected the plan from the second notification and completed from the first, got: %v", webhook.payloads)
+	}
+	if !strings.Contains(webhook.payloads[0]["text"].(string), "about to delete from project test-nuke-123456:\nunowned:\n- ComputeDisks: boot, scratch") {
+		t.Errorf("expected the plan message, got: %v", webhook.payloads[0]["text"])
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	for _, notification := range []config.Notification{
+		{},
+		{URL: "http://localhost", Format: "email"},
+		{URL: "http://localhost", Events: []string{"started"}},
+		{URL: "http://localhost", Template: "{{.Project"},
+	} {
+		if _, err := New([]config.Notification{notification}); err == nil {
+			t.Errorf("expected an error for %+v", notification)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package pricing
+
+// Default - the bundled price table, on-demand list prices for us-central1
+// Update it from https://cloud.google.com/compute/all-pricing, or override single prices with --price-table
+func Default() *Table {
+	standard4 := map[string]float64{"standard": 4, "highmem": 8, "highcpu": 1}
+	return &Table{
+		HoursPerMonth: 730,
+		Machines: map[string]MachineFamily{
+			"e2":  {CPU: 0.021811, MemoryGB: 0.002923, MemoryPerCPU: standard4},
+			"n1":  {CPU: 0.031611, MemoryGB: 0.004237, MemoryPerCPU: map[string]float64{"standard": 3.75, "highmem": 6.5, "highcpu": 0.9}},
+			"n2":  {CPU: 0.031611, MemoryGB: 0.004237, MemoryPerCPU: standard4},
+			"n2d": {CPU: 0.027502, MemoryGB: 0.003686, MemoryPerCPU: standard4},
+			"t2d": {CPU: 0.027502, MemoryGB: 0.003686, MemoryPerCPU: map[string]float64{"standard": 4}},
+			"c2":  {CPU: 0.03398, MemoryGB: 0.00455, MemoryPerCPU: map[string]float64{"standard": 4}},
+			"c2d": {CPU: 0.029563, MemoryGB: 0.003959, MemoryPerCPU: map[string]float64{"standard": 4, "highmem": 8, "highcpu": 2}},
+			"c3":  {CPU: 0.03465, MemoryGB: 0.003938, MemoryPerCPU: map[string]float64{"standard": 4, "highmem": 8, "highcpu": 2}},
+		},
+		SharedCore: map[string]float64{
+			"e2-micro":  0.008376,
+			"e2-small":  0.016751,
+			"e2-medium": 0.033503,
+			"f1-micro":  0.0076,
+			"g1-small":  0.0257,
+		},
+		Disks: map[string]float64{
+			"pd-standard": 0.04,
+			"pd-balanced": 0.10,
+			"pd-ssd":      0.17,
+			"pd-extreme":  0.125,
+		},
+		DefaultDiskType:   "pd-balanced",
+		PreemptibleFactor: 0.3,
+		GKECluster:        0.10,
+		VPNTunnel:         0.05,
+		ForwardingRule:    0.025,
+		StaticAddress:     0.01,
+		Regions: map[string]float64{
+			"us-central1":             1,
+			"us-east1":                1,
+			"us-west1":                1,
+			"us-east4":                1.13,
+			"us-west2":                1.2,
+			"northamerica-northeast1": 1.1,
+			"southamerica-east1":      1.59,
+			"europe-west1":            1.1,
+			"europe-west2":            1.16,
+			"europe-west3":            1.2,
+			"europe-west4":            1.1,
+			"europe-north1":           1.1,
+			"asia-east1":              1.16,
+			"asia-northeast1":         1.29,
+			"asia-south1":             1.2,
+			"asia-southeast1":         1.23,
+			"australia-southeast1":    1.42,
+		},
+	}
+}
@@ -0,0 +1,180 @@
+// Package pricing - estimates the monthly cost of resources from a local price table, without calling the billing API
+package pricing
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Table - on-demand list prices in USD for us-central1, scaled by the region multipliers
+type Table struct {
+	// HoursPerMonth - hours billed in a month for hourly prices
+	HoursPerMonth float64 `yaml:"hoursPerMonth"`
+	// Machines - per machine family e.g. n1 or e2
+	Machines map[string]MachineFamily `yaml:"machines"`
+	// SharedCore - hourly price of shared core machine types e.g. e2-micro
+	SharedCore map[string]float64 `yaml:"sharedCore"`
+	// Disks - price per GB per month, per disk type e.g. pd-ssd
+	Disks map[string]float64 `yaml:"disks"`
+	// DefaultDiskType - disk type of attached disks, as instances don't say which type their disks are
+	DefaultDiskType string `yaml:"defaultDiskType"`
+	// PreemptibleFactor - fraction of the on-demand price paid for preemptible machines
+	PreemptibleFactor float64 `yaml:"preemptibleFactor"`
+	// GKECluster - hourly cluster management fee
+	GKECluster float64 `yaml:"gkeCluster"`
+	// VPNTunnel - hourly price of a vpn tunnel
+	VPNTunnel float64 `yaml:"vpnTunnel"`
+	// ForwardingRule - hourly price of a forwarding rule
+	ForwardingRule float64 `yaml:"forwardingRule"`
+	// StaticAddress - hourly price of a static external address which isn't used by anything
+	StaticAddress float64 `yaml:"staticAddress"`
+	// Regions - price multiplier per region, regions not in the table are priced as us-central1
+	Regions map[string]float64 `yaml:"regions"`
+}
+
+// MachineFamily - hourly prices of a machine family, and the memory of its predefined machine types
+type MachineFamily struct {
+	CPU      float64 `yaml:"cpu"`
+	MemoryGB float64 `yaml:"memoryGB"`
+	// MemoryPerCPU - GB of memory per vCPU, per predefined machine type e.g. standard or highmem
+	MemoryPerCPU map[string]float64 `yaml:"memoryPerCPU"`
+}
+
+// Load - the bundled price table, updated with the prices in a yaml or json file
+// The file only needs the prices that changed, e.g. disks: {pd-ssd: 0.18}
+func Load(path string) (*Table, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	update := &Table{}
+	if err := yaml.UnmarshalStrict(content, update); err != nil {
+		return nil, fmt.Errorf("invalid price table %v: %v", path, err)
+	}
+
+	table := Default()
+	if update.HoursPerMonth > 0 {
+		table.HoursPerMonth = update.HoursPerMonth
+	}
+	for family, prices := range update.Machines {
+		table.Machines[family] = prices
+	}
+	for machineType, price := range update.SharedCore {
+		table.SharedCore[machineType] = price
+	}
+	for diskType, price := range update.Disks {
+		table.Disks[diskType] = price
+	}
+	if update.DefaultDiskType != "" {
+		table.DefaultDiskType = update.DefaultDiskType
+	}
+	if update.PreemptibleFactor > 0 {
+		table.PreemptibleFactor = update.PreemptibleFactor
+	}
+	if update.GKECluster > 0 {
+		table.GKECluster = update.GKECluster
+	}
+	if update.VPNTunnel > 0 {
+		table.VPNTunnel = update.VPNTunnel
+	}
+	if update.ForwardingRule > 0 {
+		table.ForwardingRule = update.ForwardingRule
+	}
+	if update.StaticAddress > 0 {
+		table.StaticAddress = update.StaticAddress
+	}
+	for region, factor := range update.Regions {
+		table.Regions[region] = factor
+	}
+	return table, nil
+}
+
+// Monthly - monthly cost of an hourly price in the region
+func (t *Table) Monthly(hourly float64, region string) float64 {
+	return hourly * t.HoursPerMonth * t.regionFactor(region)
+}
+
+// Machine - monthly cost of a machine type e.g. n1-standard-4 or e2-custom-2-4096, ok is false for unknown machine types
+func (t *Table) Machine(machineType, region string, preemptible bool) (monthly float64, ok bool) {
+	machineType = lastSegment(machineType)
+	hourly, ok := t.SharedCore[machineType]
+	if !ok {
+		hourly, ok = t.machineHourly(machineType)
+	}
+	if !ok {
+		return 0, false
+	}
+	if preemptible {
+		hourly *= t.PreemptibleFactor
+	}
+	return t.Monthly(hourly, region), true
+}
+
+// Disk - monthly cost of a disk of the type e.g. pd-ssd, unknown types are priced as the default disk type
+func (t *Table) Disk(diskType string, sizeGB int64, region string) float64 {
+	price, ok := t.Disks[lastSegment(diskType)]
+	if !ok {
+		price = t.Disks[t.DefaultDiskType]
+	}
+	return price * float64(sizeGB) * t.regionFactor(region)
+}
+
+// machineHourly - family-type-cpus for predefined machine types, family-custom-cpus-memoryMB for custom ones
+func (t *Table) machineHourly(machineType string) (float64, bool) {
+	parts := strings.Split(machineType, "-")
+	if len(parts) < 3 {
+		return 0, false
+	}
+	family, exists := t.Machines[parts[0]]
+	if !exists {
+		return 0, false
+	}
+	cpus, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, false
+	}
+
+	var memoryGB float64
+	if parts[1] == "custom" {
+		if len(parts) < 4 {
+			return 0, false
+		}
+		memoryMB, err := strconv.ParseFloat(parts[3], 64)
+		if err != nil {
+			return 0, false
+		}
+		memoryGB = memoryMB / 1024
+	} else {
+		memoryPerCPU, exists := family.MemoryPerCPU[parts[1]]
+		if !exists {
+			return 0, false
+		}
+		memoryGB = cpus * memoryPerCPU
+	}
+	return cpus*family.CPU + memoryGB*family.MemoryGB, true
+}
+
+func (t *Table) regionFactor(region string) float64 {
+	if factor, exists := t.Regions[region]; exists {
+		return factor
+	}
+	return 1
+}
+
+// Region - the region of a zone e.g. europe-west1-b gives europe-west1, regions are returned as they are
+func Region(location string) string {
+	location = lastSegment(location)
+	if i := strings.LastIndex(location, "-"); i >= 0 && len(location)-i == 2 {
+		return location[:i]
+	}
+	return location
+}
+
+// lastSegment - the name at the end of a url e.g. zones/europe-west1-b/diskTypes/pd-ssd gives pd-ssd
+func lastSegment(value string) string {
+	return value[strings.LastIndex(value, "/")+1:]
+}
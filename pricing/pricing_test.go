@@ -0,0 +1,73 @@
+package pricing
+
+import (
+	"io/ioutil"
+	"math"
+	"path/filepath"
+	"testing"
+)
+
+func TestMachine(t *testing.T) {
+	table := Default()
+	for _, test := range []struct {
+		machineType string
+		region      string
+		preemptible bool
+		expected    float64
+	}{
+		{"n1-standard-4", "us-central1", false, (4*0.031611 + 15*0.004237) * 730},
+		{"zones/europe-west1-b/machineTypes/n1-standard-4", "europe-west1", false, (4*0.031611 + 15*0.004237) * 730 * 1.1},
+		{"e2-custom-2-4096", "us-central1", false, (2*0.021811 + 4*0.002923) * 730},
+		{"e2-medium", "us-central1", true, 0.033503 * 0.3 * 730},
+	} {
+		monthly, ok := table.Machine(test.machineType, test.region, test.preemptible)
+		if !ok || math.Abs(monthly-test.expected) > 0.001 {
+			t.Errorf("expected %v in %v to cost %.2f, got: %.2f (%v)", test.machineType, test.region, test.expected, monthly, ok)
+		}
+	}
+	for _, machineType := range []string{"a2-highgpu-1g", "n1-ultramem-40", "n1-custom"} {
+		if _, ok := table.Machine(machineType, "us-central1", false); ok {
+			t.Errorf("expected no price for %v", machineType)
+		}
+	}
+}
+
+func TestDiskAndRegion(t *testing.T) {
+	table := Default()
+	if monthly := table.Disk("zones/us-central1-a/diskTypes/pd-ssd", 100, "us-central1"); math.Abs(monthly-17) > 0.001 {
+		t.Errorf("expected 100GB of pd-ssd to cost 17.00, got: %.2f", monthly)
+	}
+	if monthly := table.Disk("hyperdisk-future", 10, "us-central1"); math.Abs(monthly-1) > 0.001 {
+		t.Errorf("expected unknown disk types to be priced as pd-balanced, got: %.2f", monthly)
+	}
+	for location, expected := range map[string]string{"europe-west1-b": "europe-west1", "europe-west1": "europe-west1", "projects/p/zones/us-east4-c": "us-east4"} {
+		if region := Region(location); region != expected {
+			t.Errorf("expected the region of %v to be %v, got: %v", location, expected, region)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.yaml")
+	content := "disks:\n  pd-ssd: 0.2\nregions:\n  me-west1: 1.3\n"
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	table, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Disks["pd-ssd"] != 0.2 || table.Disks["pd-standard"] != 0.04 {
+		t.Errorf("expected pd-ssd to be updated and the other disk prices kept, got: %v", table.Disks)
+	}
+	if table.Regions["me-west1"] != 1.3 || table.Regions["europe-west1"] != 1.1 {
+		t.Errorf("expected me-west1 to be added to the bundled regions, got: %v", table.Regions)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("disk:\n  pd-ssd: 0.2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Error("expected unknown fields to be rejected")
+	}
+}
@@ -0,0 +1,274 @@
+// Package report - summary of a run, per resource type, used for notifications and the plan
+package report
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Report - summary of a run, safe to update from the goroutine of every resource type at once
+type Report struct {
+	Project string    `json:"project"`
+	DryRun  bool      `json:"dryRun"`
+	Start   time.Time `json:"start"`
+	// Duration - nanoseconds in json
+	Duration time.Duration `json:"duration"`
+	// Error - the error the run failed with, empty when it succeeded
+	Error     string            `json:"error,omitempty"`
+	Resources []*ResourceReport `json:"resources"`
+	// MonthlyCost - estimated monthly cost in USD of every item listed for deletion
+	MonthlyCost float64 `json:"monthlyCost"`
+
+	mutex sync.Mutex
+}
+
+// ResourceReport - summary of a single resource type
+type ResourceReport struct {
+	Type string `json:"type"`
+	// Items - items listed for deletion at the start of the run
+	Items   []string `json:"items"`
+	Deleted int      `json:"deleted"`
+	// Remaining - items still left when the run finished
+	Remaining []string `json:"remaining,omitempty"`
+	Error     string   `json:"error,omitempty"`
+	// Owners - owner of each listed item, from its owner labels, created-by metadata or the audit log
+	Owners map[string]string `json:"owners,omitempty"`
+	// Costs - estimated monthly cost in USD of each listed item, items without a cost are left out
+	Costs map[string]float64 `json:"costs,omitempty"`
+	// MonthlyCost - estimated monthly cost in USD of the listed items
+	MonthlyCost float64 `json:"monthlyCost,omitempty"`
+	// Snapshots - snapshot taken of each disk before it was deleted, keyed by disk name
+	Snapshots map[string]string `json:"snapshots,omitempty"`
+	// Notes - caveats and details of items, keyed by item e.g. when the name of a Cloud SQL instance can be reused or how old a key is
+	Notes map[string]string `json:"notes,omitempty"`
+}
+
+// Unowned - owner of items with no owner label, created-by metadata or audit log entry
+const Unowned = "unowned"
+
+// OwnerReport - the items of a single owner, per resource type
+type OwnerReport struct {
+	Owner       string            `json:"owner"`
+	Resources   []*ResourceReport `json:"resources"`
+	MonthlyCost float64           `json:"monthlyCost,omitempty"`
+}
+
+// New - starts the report of a run
+func New(project string, dryRun bool) *Report {
+	return &Report{
+		Project:   project,
+		DryRun:    dryRun,
+		Start:     time.Now(),
+		Resources: []*ResourceReport{},
+	}
+}
+
+// SetItems - records the items listed for the resource type, and totals their costs
+func (r *Report) SetItems(resourceType string, items []string) {
+	r.update(resourceType, func(resource *ResourceReport) {
+		resource.Items = append([]string{}, items...)
+		resource.MonthlyCost = 0
+		for _, item := range items {
+			resource.MonthlyCost += resource.Costs[item]
+		}
+	})
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.MonthlyCost = 0
+	for _, resource := range r.Resources {
+		r.MonthlyCost += resource.MonthlyCost
+	}
+}
+
+// AddDeleted - counts an item of the resource type as deleted
+func (r *Report) AddDeleted(resourceType string) {
+	r.update(resourceType, func(resource *ResourceReport) {
+		resource.Deleted++
+	})
+}
+
+// SetResult - records the items left over and the error, if any, once the resource type is done
+func (r *Report) SetResult(resourceType string, remaining []string, err error) {
+	r.update(resourceType, func(resource *ResourceReport) {
+		resource.Remaining = append([]string{}, remaining...)
+		if err != nil {
+			resource.Error = err.Error()
+		}
+	})
+}
+
+// SetOwner - records the owner of an item of the resource type
+func (r *Report) SetOwner(resourceType, item, owner string) {
+	r.update(resourceType, func(resource *ResourceReport) {
+		if resource.Owners == nil {
+			resource.Owners = make(map[string]string)
+		}
+		resource.Owners[item] = owner
+	})
+}
+
+// SetCost - records the estimated monthly cost of an item of the resource type, before its items are set
+func (r *Report) SetCost(resourceType, item string, monthlyCost float64) {
+	r.update(resourceType, func(resource *ResourceReport) {
+		if resource.Costs == nil {
+			resource.Costs = make(map[string]float64)
+		}
+		resource.Costs[item] = monthlyCost
+	})
+}
+
+// AddSnapshot - records the snapshot taken of a disk deleted by the resource type
+func (r *Report) AddSnapshot(resourceType, disk, snapshot string) {
+	r.update(resourceType, func(resource *ResourceReport) {
+		if resource.Snapshots == nil {
+			resource.Snapshots = make(map[string]string)
+		}
+		resource.Snapshots[disk] = snapshot
+	})
+}
+
+// AddNote - records a caveat of an item deleted by the resource type
+func (r *Report) AddNote(resourceType, item, note string) {
+	r.update(resourceType, func(resource *ResourceReport) {
+		if resource.Notes == nil {
+			resource.Notes = make(map[string]string)
+		}
+		resource.Notes[item] = note
+	})
+}
+
+// SnapshotOf - the snapshot already taken of the disk, so retried deletions don't snapshot it again
+func (r *Report) SnapshotOf(resourceType, disk string) (snapshot string, taken bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, resource := range r.Resources {
+		if resource.Type == resourceType {
+			snapshot, taken = resource.Snapshots[disk]
+		}
+	}
+	return snapshot, taken
+}
+
+// Finish - records the duration and error of the run
+func (r *Report) Finish(err error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.Duration = time.Since(r.Start)
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// Resource - the report of the resource type, nil when it has none
+func (r *Report) Resource(resourceType string) *ResourceReport {
+	if r == nil {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, resource := range r.Resources {
+		if resource.Type == resourceType {
+			return resource
+		}
+	}
+	return nil
+}
+
+// Failures - resource types which failed or still have items left
+func (r *Report) Failures() []*ResourceReport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	failures := []*ResourceReport{}
+	for _, resource := range r.Resources {
+		if resource.Error != "" || len(resource.Remaining) > 0 {
+			failures = append(failures, resource)
+		}
+	}
+	return failures
+}
+
+// Planned - resource types with items to delete, sorted by type
+func (r *Report) Planned() []*ResourceReport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	planned := []*ResourceReport{}
+	for _, resource := range r.Resources {
+		if len(resource.Items) > 0 {
+			planned = append(planned, resource)
+		}
+	}
+	return planned
+}
+
+// ByOwner - the planned items grouped by owner, sorted by owner with unowned items last
+// Only the type and items are set on the resource reports of each owner
+func (r *Report) ByOwner() []*OwnerReport {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	owners := make(map[string]*OwnerReport)
+	names := []string{}
+	for _, resource := range r.Resources {
+		for _, item := range resource.Items {
+			owner := resource.Owners[item]
+			if owner == "" {
+				owner = Unowned
+			}
+			ownerReport, exists := owners[owner]
+			if !exists {
+				ownerReport = &OwnerReport{Owner: owner, Resources: []*ResourceReport{}}
+				owners[owner] = ownerReport
+				names = append(names, owner)
+			}
+			// Resources are sorted by type, so items of the same type are always added one after the other
+			last := len(ownerReport.Resources) - 1
+			if last < 0 || ownerReport.Resources[last].Type != resource.Type {
+				ownerReport.Resources = append(ownerReport.Resources, &ResourceReport{Type: resource.Type, Items: []string{}})
+				last++
+			}
+			ownerReport.Resources[last].Items = append(ownerReport.Resources[last].Items, item)
+			ownerReport.Resources[last].MonthlyCost += resource.Costs[item]
+			ownerReport.MonthlyCost += resource.Costs[item]
+		}
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == Unowned) != (names[j] == Unowned) {
+			return names[j] == Unowned
+		}
+		return names[i] < names[j]
+	})
+	byOwner := []*OwnerReport{}
+	for _, name := range names {
+		byOwner = append(byOwner, owners[name])
+	}
+	return byOwner
+}
+
+// update - applies the change to the resource type, adding it in sorted order on first use
+func (r *Report) update(resourceType string, change func(resource *ResourceReport)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	i := sort.Search(len(r.Resources), func(i int) bool {
+		return r.Resources[i].Type >= resourceType
+	})
+	if i == len(r.Resources) || r.Resources[i].Type != resourceType {
+		r.Resources = append(r.Resources, nil)
+		copy(r.Resources[i+1:], r.Resources[i:])
+		r.Resources[i] = &ResourceReport{Type: resourceType, Items: []string{}}
+	}
+	change(r.Resources[i])
+}
+
+type contextKey struct{}
+
+// NewContext - carries the report of the run down to the deletion of every item
+func NewContext(ctx context.Context, r *Report) context.Context {
+	return context.WithValue(ctx, contextKey{}, r)
+}
+
+// FromContext - the report of the run, or nil outside of a run
+func FromContext(ctx context.Context) *Report {
+	r, _ := ctx.Value(contextKey{}).(*Report)
+	return r
+}